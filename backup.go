@@ -0,0 +1,42 @@
+package goutils
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// Backup copies the file at path to a backup alongside it, named with a .bak
+// extension and numbered by UniquePath so existing backups are never
+// overwritten. The path of the new backup is returned.
+//  config.yml --> config.yml.bak --> config.yml.1.bak
+func Backup(fs afero.Fs, path string) (string, error) {
+	backup, err := UniquePath(fs, path+".bak")
+	if err != nil {
+		return "", err
+	}
+	if _, err := CopyFS(fs, path, backup); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// MoveToTrash moves the file at path into trashDir, creating trashDir if
+// needed and numbering the name by UniquePath to avoid replacing earlier
+// trashed files. The new location of the file is returned.
+func MoveToTrash(fs afero.Fs, path, trashDir string) (string, error) {
+	if _, err := fs.Stat(path); err != nil {
+		return "", err
+	}
+	if err := fs.MkdirAll(trashDir, 0755); err != nil {
+		return "", err
+	}
+	trashed, err := UniquePath(fs, filepath.Join(trashDir, filepath.Base(path)))
+	if err != nil {
+		return "", err
+	}
+	if err := fs.Rename(path, trashed); err != nil {
+		return "", err
+	}
+	return trashed, nil
+}
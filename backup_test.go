@@ -0,0 +1,73 @@
+package goutils
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestBackup(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	expected := []string{
+		"/etc/config.yml.bak",
+		"/etc/config.yml.1.bak",
+		"/etc/config.yml.2.bak",
+	}
+	contents := []string{"first", "second", "third"}
+
+	for i, e := range expected {
+		afero.WriteFile(fs, "/etc/config.yml", []byte(contents[i]), 0644)
+		res, err := Backup(fs, "/etc/config.yml")
+		if err != nil {
+			t.Fatalf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if e != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, res)
+		}
+	}
+	for i, e := range expected {
+		content, _ := afero.ReadFile(fs, e)
+		if contents[i] != string(content) {
+			t.Errorf("Test %d failed. Expected %s got %s", i, contents[i], content)
+		}
+	}
+
+	if _, err := Backup(fs, "/etc/missing.yml"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestMoveToTrash(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	type test struct {
+		path     string
+		content  string
+		expected string
+	}
+	data := []test{
+		{"/work/notes.txt", "first", "/trash/notes.txt"},
+		{"/work/sub/notes.txt", "second", "/trash/notes.1.txt"},
+	}
+
+	for i, d := range data {
+		afero.WriteFile(fs, d.path, []byte(d.content), 0644)
+		res, err := MoveToTrash(fs, d.path, "/trash")
+		if err != nil {
+			t.Fatalf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+		if exists, _ := Exists(fs, d.path); exists {
+			t.Errorf("Test %d failed. Expected %s to be removed", i, d.path)
+		}
+		content, _ := afero.ReadFile(fs, res)
+		if d.content != string(content) {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.content, content)
+		}
+	}
+
+	if _, err := MoveToTrash(fs, "/work/missing.txt", "/trash"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
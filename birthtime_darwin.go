@@ -0,0 +1,16 @@
+package goutils
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// birthTime returns the creation time recorded by the OsFs on darwin
+func birthTime(info os.FileInfo) (time.Time, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(st.Birthtimespec.Unix()), true
+}
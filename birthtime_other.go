@@ -0,0 +1,14 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package goutils
+
+import (
+	"os"
+	"time"
+)
+
+// birthTime reports no creation time on platforms that do not expose it
+func birthTime(info os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
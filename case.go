@@ -0,0 +1,101 @@
+package goutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// IsCaseInsensitiveFS probes whether names in dir are matched without regard
+// to case by creating a temporary file and checking if its upper cased name
+// also resolves. The probe file is always removed.
+// Linux filesystems are usually case-sensitive, while macOS and windows
+// default to case-insensitive but case-preserving. MemMapFs is case-sensitive.
+func IsCaseInsensitiveFS(fs afero.Fs, dir string) (bool, error) {
+	f, err := afero.TempFile(fs, dir, ".goutils-case-probe")
+	if err != nil {
+		return false, err
+	}
+	name := f.Name()
+	defer fs.Remove(name)
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+	upper := filepath.Join(filepath.Dir(name), strings.ToUpper(filepath.Base(name)))
+	_, err = fs.Stat(upper)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// NormalizeExtensionCase renames the files below root so their extensions
+// are all lower case, or upper case if lower is false, and returns the new
+// paths of the renamed files. Dotfiles without a further extension, such as
+// ".Rprofile", are left alone. On a case-insensitive filesystem each file is
+// renamed through a temporary name, elsewhere an existing file at the new
+// path results in an error rather than being overwritten.
+func NormalizeExtensionCase(fs afero.Fs, root string, lower bool) ([]string, error) {
+	var paths []string
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if info.IsDir() || ext == "" || ext == info.Name() {
+			return nil
+		}
+		if ext != casedExtension(ext, lower) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	renamed := []string{}
+	if len(paths) == 0 {
+		return renamed, nil
+	}
+	insensitive, err := IsCaseInsensitiveFS(fs, root)
+	if err != nil {
+		return renamed, err
+	}
+	for _, path := range paths {
+		ext := filepath.Ext(path)
+		newPath := strings.TrimSuffix(path, ext) + casedExtension(ext, lower)
+		if insensitive {
+			tmp, err := UniquePath(fs, path+".tmp")
+			if err != nil {
+				return renamed, err
+			}
+			if err := fs.Rename(path, tmp); err != nil {
+				return renamed, err
+			}
+			path = tmp
+		} else if exists, err := Exists(fs, newPath); err != nil || exists {
+			if err == nil {
+				err = fmt.Errorf("cannot rename %s, %s already exists", path, newPath)
+			}
+			return renamed, err
+		}
+		if err := fs.Rename(path, newPath); err != nil {
+			return renamed, err
+		}
+		renamed = append(renamed, newPath)
+	}
+	return renamed, nil
+}
+
+func casedExtension(ext string, lower bool) string {
+	if lower {
+		return strings.ToLower(ext)
+	}
+	return strings.ToUpper(ext)
+}
@@ -0,0 +1,138 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// foldingFs simulates a case-insensitive filesystem by lower casing names
+type foldingFs struct {
+	afero.Fs
+}
+
+func (fs foldingFs) fold(name string) string {
+	return filepath.Join(filepath.Dir(name), strings.ToLower(filepath.Base(name)))
+}
+
+func (fs foldingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	return fs.Fs.OpenFile(fs.fold(name), flag, perm)
+}
+
+func (fs foldingFs) Open(name string) (afero.File, error) {
+	return fs.Fs.Open(fs.fold(name))
+}
+
+func (fs foldingFs) Stat(name string) (os.FileInfo, error) {
+	return fs.Fs.Stat(fs.fold(name))
+}
+
+func (fs foldingFs) Remove(name string) error {
+	return fs.Fs.Remove(fs.fold(name))
+}
+
+func (fs foldingFs) Rename(oldname, newname string) error {
+	return fs.Fs.Rename(fs.fold(oldname), fs.fold(newname))
+}
+
+func TestIsCaseInsensitiveFS(t *testing.T) {
+	type test struct {
+		fs       afero.Fs
+		expected bool
+	}
+	data := []test{
+		{afero.NewMemMapFs(), false},
+		{foldingFs{afero.NewMemMapFs()}, true},
+	}
+
+	for i, d := range data {
+		d.fs.MkdirAll("/probe", 0755)
+		res, err := IsCaseInsensitiveFS(d.fs, "/probe")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+		}
+		if empty, _ := IsEmptyDir(d.fs, "/probe"); !empty {
+			t.Errorf("Test %d failed. Expected the probe file to be removed", i)
+		}
+	}
+
+	if _, err := IsCaseInsensitiveFS(afero.NewReadOnlyFs(afero.NewMemMapFs()), "/probe"); err == nil {
+		t.Error("Expected error when the probe cannot be created")
+	}
+}
+
+func TestNormalizeExtensionCase(t *testing.T) {
+	files := []string{"a.TXT", "b.Md", "c.txt", "sub/D.JPG", "sub/archive.tar.GZ", "noext", ".Rprofile"}
+
+	type test struct {
+		lower        bool
+		renamed      []string
+		expectedTree []string
+	}
+	data := []test{
+		{
+			true,
+			[]string{"a.txt", "b.md", "sub/D.jpg", "sub/archive.tar.gz"},
+			[]string{".Rprofile", "a.txt", "b.md", "c.txt", "noext", "sub/D.jpg", "sub/archive.tar.gz"},
+		},
+		{
+			false,
+			[]string{"b.MD", "c.TXT"},
+			[]string{".Rprofile", "a.TXT", "b.MD", "c.TXT", "noext", "sub/D.JPG", "sub/archive.tar.GZ"},
+		},
+	}
+	for i, d := range data {
+		fs := newTestTree("/root", files)
+		res, err := NormalizeExtensionCase(fs, filepath.FromSlash("/root"), d.lower)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		var renamed []string
+		for _, r := range res {
+			rel, _ := filepath.Rel(filepath.FromSlash("/root"), r)
+			renamed = append(renamed, filepath.ToSlash(rel))
+		}
+		if strings.Join(d.renamed, ",") != strings.Join(renamed, ",") {
+			t.Errorf("Test %d failed. Expected %v renamed got %v", i, d.renamed, renamed)
+		}
+		tree, _ := TreeRelativePaths(fs, filepath.FromSlash("/root"))
+		if strings.Join(d.expectedTree, ",") != strings.Join(tree, ",") {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expectedTree, tree)
+		}
+	}
+
+	fs := newTestTree("/root", []string{"a.TXT", "a.txt"})
+	if _, err := NormalizeExtensionCase(fs, filepath.FromSlash("/root"), true); err == nil {
+		t.Error("Expected error for a colliding rename")
+	}
+}
+
+func TestNormalizeExtensionCaseOsFs(t *testing.T) {
+	fs := afero.NewOsFs()
+	root, err := afero.TempDir(fs, "", "goutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	afero.WriteFile(fs, filepath.Join(root, "Report.PDF"), []byte("pdf"), 0644)
+
+	// a case-insensitive filesystem takes the two step rename
+	res, err := NormalizeExtensionCase(fs, root, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := filepath.Join(root, "Report.pdf")
+	if len(res) != 1 || res[0] != expected {
+		t.Errorf("Expected [%s] got %v", expected, res)
+	}
+	names, _ := afero.ReadDir(fs, root)
+	if len(names) != 1 || names[0].Name() != "Report.pdf" {
+		t.Errorf("Expected only Report.pdf to remain got %v", ListFiles(names))
+	}
+}
@@ -0,0 +1,130 @@
+package goutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// Checksum streams the file at path through h and returns the hex encoded digest
+//  Checksum(fs, "run001.mod", sha256.New())
+func Checksum(fs afero.Fs, path string, h hash.Hash) (string, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h.Reset()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyChecksum checks whether the digest of the file at path matches the
+// expected hex string, ignoring case. A digest of a different length is
+// reported as a mismatch rather than an error.
+func VerifyChecksum(fs afero.Fs, path string, h hash.Hash, expectedHex string) (bool, error) {
+	sum, err := Checksum(fs, path, h)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(sum, expectedHex), nil
+}
+
+// DirFingerprint produces a single hex digest summarizing the tree at root.
+// The relative path, size and content of each file are hashed in sorted
+// path order, so renaming or modifying any file changes the fingerprint.
+//  DirFingerprint(fs, "src", sha256.New)
+func DirFingerprint(fs afero.Fs, root string, newHash func() hash.Hash) (string, error) {
+	paths, err := TreeRelativePaths(fs, root)
+	if err != nil {
+		return "", err
+	}
+	h := newHash()
+	for _, p := range paths {
+		f, err := fs.Open(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil {
+			return "", err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return "", err
+		}
+		// the size delimits the content from the next entry
+		fmt.Fprintf(h, "%s\x00%d\x00", p, info.Size())
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyManifest checks the files below root against a manifest mapping
+// slash separated relative paths to expected hex digests. The sorted paths
+// of files that do not exist and of files whose digest differs are returned.
+// Files in root but not in the manifest are not reported.
+func VerifyManifest(fs afero.Fs, root string, manifest map[string]string, newHash func() hash.Hash) (missing, mismatched []string, err error) {
+	paths := make([]string, 0, len(manifest))
+	for p := range manifest {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	h := newHash()
+	for _, p := range paths {
+		ok, err := VerifyChecksum(fs, filepath.Join(root, filepath.FromSlash(p)), h, manifest[p])
+		switch {
+		case os.IsNotExist(err):
+			missing = append(missing, p)
+		case err != nil:
+			return nil, nil, err
+		case !ok:
+			mismatched = append(mismatched, p)
+		}
+	}
+	return missing, mismatched, nil
+}
+
+// GenerateManifest walks root and maps the slash separated relative path of
+// every file to its hex digest, the manifest VerifyManifest checks against.
+// Maps are serialized by encoding/json in sorted key order, so the same tree
+// always produces the same manifest file.
+func GenerateManifest(fs afero.Fs, root string, newHash func() hash.Hash) (map[string]string, error) {
+	paths, err := TreeRelativePaths(fs, root)
+	if err != nil {
+		return nil, err
+	}
+	manifest := make(map[string]string, len(paths))
+	h := newHash()
+	for _, p := range paths {
+		sum, err := Checksum(fs, filepath.Join(root, filepath.FromSlash(p)), h)
+		if err != nil {
+			return nil, err
+		}
+		manifest[p] = sum
+	}
+	return manifest, nil
+}
+
+// HashedName returns a filesystem safe hex name derived from parts, for use
+// as a content addressed cache file name. Each part is length prefixed
+// before hashing, so ("ab", "c") and ("a", "bc") give different names.
+//  HashedName("run001.mod", "v1.2.0") + ".json"
+func HashedName(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%d:%s", len(p), p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,211 @@
+package goutils
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// sha256 of "hello world"
+const helloSHA256 = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+func TestChecksum(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "file.txt", []byte("hello world"), 0644)
+
+	res, err := Checksum(fs, "file.txt", sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != helloSHA256 {
+		t.Errorf("Expected %s got %s", helloSHA256, res)
+	}
+	if _, err := Checksum(fs, "missing.txt", sha256.New()); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	type test struct {
+		path     string
+		expected string
+		match    bool
+		err      bool
+	}
+	data := []test{
+		{"file.txt", helloSHA256, true, false},
+		{"file.txt", strings.ToUpper(helloSHA256), true, false},
+		{"file.txt", strings.Replace(helloSHA256, "b9", "00", 1), false, false},
+		{"file.txt", "5eb63bbbe01eeed093cb22bb8f5acdc3", false, false},
+		{"missing.txt", helloSHA256, false, true},
+	}
+
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "file.txt", []byte("hello world"), 0644)
+	for i, d := range data {
+		res, err := VerifyChecksum(fs, d.path, sha256.New(), d.expected)
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if res != d.match {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.match, res)
+		}
+	}
+
+	// the hasher is reset so it can be reused across calls
+	h := md5.New()
+	for i := 0; i < 2; i++ {
+		if ok, _ := VerifyChecksum(fs, "file.txt", h, "5eb63bbbe01eeed093cb22bb8f5acdc3"); !ok {
+			t.Errorf("Expected md5 to match on call %d", i)
+		}
+	}
+}
+
+func TestDirFingerprint(t *testing.T) {
+	files := []string{"a.txt", "sub/b.txt", "sub/deep/c.txt"}
+	base, err := DirFingerprint(newTestTree("/root", files), "/root", sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	same, _ := DirFingerprint(newTestTree("/root", files), "/root", sha256.New)
+	if base != same {
+		t.Errorf("Expected identical trees to match, got %s and %s", base, same)
+	}
+	moved, _ := DirFingerprint(newTestTree("/elsewhere", files), "/elsewhere", sha256.New)
+	if base != moved {
+		t.Errorf("Expected fingerprint to be independent of root, got %s and %s", base, moved)
+	}
+
+	type test struct {
+		name   string
+		mutate func(fs afero.Fs)
+	}
+	data := []test{
+		{"content", func(fs afero.Fs) { afero.WriteFile(fs, "/root/a.txt", []byte("changed"), 0644) }},
+		{"rename", func(fs afero.Fs) { fs.Rename("/root/sub/b.txt", "/root/sub/renamed.txt") }},
+		{"add", func(fs afero.Fs) { afero.WriteFile(fs, "/root/new.txt", nil, 0644) }},
+		{"remove", func(fs afero.Fs) { fs.Remove("/root/sub/deep/c.txt") }},
+	}
+	for i, d := range data {
+		fs := newTestTree("/root", files)
+		d.mutate(fs)
+		res, err := DirFingerprint(fs, "/root", sha256.New)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if res == base {
+			t.Errorf("Test %d failed. Expected %s to change the fingerprint", i, d.name)
+		}
+	}
+}
+
+func TestVerifyManifest(t *testing.T) {
+	// each file of the test tree contains its own relative path
+	manifest := map[string]string{
+		"a.txt":          "18b7cb099a9ea3f50ba899b5ba81e0d377a5f3b16f8f6eeb8b3e58cd4692b993",
+		"sub/b.txt":      "8792787d36836ac259ca93c2cd818a852c2cd1009b0243010c188f028c99a040",
+		"sub/deep/c.txt": "43e8a4c909385b604e4ef746f0d8cf4d0cbdc4ebcbb6ab824d991f2226340166",
+	}
+	files := []string{"a.txt", "sub/b.txt", "sub/deep/c.txt", "extra.txt"}
+
+	type test struct {
+		mutate     func(fs afero.Fs)
+		missing    []string
+		mismatched []string
+	}
+	data := []test{
+		{func(fs afero.Fs) {}, nil, nil},
+		{func(fs afero.Fs) { fs.Remove("/root/sub/b.txt") }, []string{"sub/b.txt"}, nil},
+		{func(fs afero.Fs) { afero.WriteFile(fs, "/root/a.txt", []byte("tampered"), 0644) }, nil, []string{"a.txt"}},
+		{
+			func(fs afero.Fs) {
+				fs.RemoveAll("/root/sub")
+				afero.WriteFile(fs, "/root/a.txt", []byte("tampered"), 0644)
+			},
+			[]string{"sub/b.txt", "sub/deep/c.txt"},
+			[]string{"a.txt"},
+		},
+	}
+	for i, d := range data {
+		fs := newTestTree("/root", files)
+		d.mutate(fs)
+		missing, mismatched, err := VerifyManifest(fs, "/root", manifest, sha256.New)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if strings.Join(d.missing, ",") != strings.Join(missing, ",") {
+			t.Errorf("Test %d failed. Expected missing %v got %v", i, d.missing, missing)
+		}
+		if strings.Join(d.mismatched, ",") != strings.Join(mismatched, ",") {
+			t.Errorf("Test %d failed. Expected mismatched %v got %v", i, d.mismatched, mismatched)
+		}
+	}
+}
+
+func TestGenerateManifest(t *testing.T) {
+	files := []string{"a.txt", ".hidden", "sub/b.txt", "sub/deep/c.txt"}
+	fs := newTestTree("/root", files)
+	fs.MkdirAll("/root/empty", 0755)
+
+	manifest, err := GenerateManifest(fs, "/root", sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest) != len(files) {
+		t.Errorf("Expected %d entries got %v", len(files), manifest)
+	}
+	for i, f := range files {
+		expected := fmt.Sprintf("%x", sha256.Sum256([]byte(f)))
+		if expected != manifest[f] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, expected, manifest[f])
+		}
+	}
+
+	missing, mismatched, err := VerifyManifest(fs, "/root", manifest, sha256.New)
+	if err != nil || len(missing) != 0 || len(mismatched) != 0 {
+		t.Errorf("Expected generated manifest to verify got %v, %v, %v", missing, mismatched, err)
+	}
+	first, _ := json.Marshal(manifest)
+	again, _ := GenerateManifest(newTestTree("/root", files), "/root", sha256.New)
+	second, _ := json.Marshal(again)
+	if string(first) != string(second) {
+		t.Errorf("Expected identical serialized manifests got %s and %s", first, second)
+	}
+}
+
+func TestHashedName(t *testing.T) {
+	name := HashedName("run001.mod", "v1.2.0")
+	if name != HashedName("run001.mod", "v1.2.0") {
+		t.Error("Expected the same parts to give the same name")
+	}
+	if len(name) != 64 || strings.Trim(name, "0123456789abcdef") != "" {
+		t.Errorf("Expected a 64 character hex name got %s", name)
+	}
+
+	distinct := [][]string{
+		{"run001.mod", "v1.2.0"},
+		{"run001.mod", "v1.2.1"},
+		{"v1.2.0", "run001.mod"},
+		{"ab", "c"},
+		{"a", "bc"},
+		{"abc"},
+		{"a:b"},
+		{"1:a", ""},
+		{""},
+		{},
+	}
+	seen := make(map[string]int)
+	for i, parts := range distinct {
+		name := HashedName(parts...)
+		if j, ok := seen[name]; ok {
+			t.Errorf("Test %d failed. Expected %q and %q to have distinct names", i, parts, distinct[j])
+		}
+		seen[name] = i
+	}
+}
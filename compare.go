@@ -0,0 +1,61 @@
+package goutils
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/spf13/afero"
+)
+
+// FilesEqual checks if two files have identical content. Sizes are compared
+// first, then the files are streamed side by side in chunks so neither is
+// fully loaded into memory.
+func FilesEqual(fs afero.Fs, a, b string) (bool, error) {
+	fa, err := fs.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := fs.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	infoA, err := fa.Stat()
+	if err != nil {
+		return false, err
+	}
+	infoB, err := fb.Stat()
+	if err != nil {
+		return false, err
+	}
+	if infoA.Size() != infoB.Size() {
+		return false, nil
+	}
+
+	bufA := make([]byte, 32*1024)
+	bufB := make([]byte, 32*1024)
+	for {
+		nA, errA := io.ReadFull(fa, bufA)
+		nB, errB := io.ReadFull(fb, bufB)
+		if !bytes.Equal(bufA[:nA], bufB[:nB]) {
+			return false, nil
+		}
+		doneA := errA == io.EOF || errA == io.ErrUnexpectedEOF
+		doneB := errB == io.EOF || errB == io.ErrUnexpectedEOF
+		if doneA && doneB {
+			return true, nil
+		}
+		if errA != nil && !doneA {
+			return false, errA
+		}
+		if errB != nil && !doneB {
+			return false, errB
+		}
+		if doneA != doneB {
+			// the file changed size while reading
+			return false, nil
+		}
+	}
+}
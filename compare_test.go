@@ -0,0 +1,43 @@
+package goutils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestFilesEqual(t *testing.T) {
+	large := strings.Repeat("0123456789", 10000)
+	type test struct {
+		a        string
+		b        string
+		expected bool
+	}
+	data := []test{
+		{"same content", "same content", true},
+		{"", "", true},
+		{large, large, true},
+		{"same size", "diff size", false},
+		{large, large[:len(large)-1] + "x", false},
+		{"short", "longer content", false},
+		{"", "x", false},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "a.txt", []byte(d.a), 0644)
+		afero.WriteFile(fs, "b.txt", []byte(d.b), 0644)
+		res, err := FilesEqual(fs, "a.txt", "b.txt")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+		}
+	}
+
+	if _, err := FilesEqual(fs, "a.txt", "missing.txt"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
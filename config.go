@@ -0,0 +1,65 @@
+package goutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/afero"
+)
+
+// XDGConfigPath returns the path of file in the config directory of app,
+// $XDG_CONFIG_HOME/app/file or ~/.config/app/file when XDG_CONFIG_HOME is
+// unset, empty or not absolute as the XDG spec requires. No directories
+// are created.
+func XDGConfigPath(app, file string) (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" || !filepath.IsAbs(configHome) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot find config directory: %s", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, app, file), nil
+}
+
+// FindConfigFiles returns the existing config files of app, named file, in
+// precedence order: the working directory, the XDG config directory given
+// by XDGConfigPath, ~/.app in the home directory and, outside of windows,
+// /etc/app. Locations that do not exist or cannot be determined are
+// skipped, and each file is listed once.
+//  FindConfigFiles(fs, "tool", "config.yml")
+//  --> [$PWD/config.yml ~/.config/tool/config.yml /etc/tool/config.yml]
+func FindConfigFiles(fs afero.Fs, app, file string) ([]string, error) {
+	var candidates []string
+	if wd, err := os.Getwd(); err == nil {
+		candidates = append(candidates, filepath.Join(wd, file))
+	}
+	if xdg, err := XDGConfigPath(app, file); err == nil {
+		candidates = append(candidates, xdg)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, "."+app, file))
+	}
+	if runtime.GOOS != "windows" {
+		candidates = append(candidates, filepath.Join("/etc", app, file))
+	}
+	found := []string{}
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		exists, err := Exists(fs, c)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,113 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// setEnv sets key for the duration of a test, returning a func restoring it
+func setEnv(key, value string) func() {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestXDGConfigPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %s", err)
+	}
+	configHome := filepath.Join(home, "xdg-config")
+
+	type test struct {
+		env      string
+		expected string
+	}
+	data := []test{
+		{configHome, filepath.Join(configHome, "tool", "config.yml")},
+		{"", filepath.Join(home, ".config", "tool", "config.yml")},
+		{"relative/config", filepath.Join(home, ".config", "tool", "config.yml")},
+	}
+	for i, d := range data {
+		restore := setEnv("XDG_CONFIG_HOME", d.env)
+		res, err := XDGConfigPath("tool", "config.yml")
+		restore()
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+
+	restore := setEnv("XDG_CONFIG_HOME", "")
+	defer restore()
+	os.Unsetenv("XDG_CONFIG_HOME")
+	if res, _ := XDGConfigPath("tool", "config.yml"); res != filepath.Join(home, ".config", "tool", "config.yml") {
+		t.Errorf("Expected fallback when unset got %s", res)
+	}
+	if exists, _ := DirExists(afero.NewOsFs(), filepath.Join(configHome, "tool")); exists {
+		t.Error("Expected no directories to be created")
+	}
+}
+
+func TestFindConfigFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the home directory is not taken from HOME on windows")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer setEnv("HOME", "/home/user")()
+	defer setEnv("XDG_CONFIG_HOME", "/home/user/.xdg")()
+
+	all := []string{
+		filepath.Join(wd, "config.yml"),
+		"/home/user/.xdg/tool/config.yml",
+		"/home/user/.tool/config.yml",
+		"/etc/tool/config.yml",
+	}
+	type test struct {
+		present  []int
+		expected []int
+	}
+	data := []test{
+		{[]int{0, 1, 2, 3}, []int{0, 1, 2, 3}},
+		{[]int{3, 1}, []int{1, 3}},
+		{[]int{2}, []int{2}},
+		{nil, nil},
+	}
+	for i, d := range data {
+		fs := afero.NewMemMapFs()
+		for _, p := range d.present {
+			afero.WriteFile(fs, all[p], nil, 0644)
+		}
+		// files for other apps and names are never returned
+		afero.WriteFile(fs, "/etc/other/config.yml", nil, 0644)
+		afero.WriteFile(fs, "/home/user/.xdg/tool/other.yml", nil, 0644)
+
+		res, err := FindConfigFiles(fs, "tool", "config.yml")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		var expected []string
+		for _, e := range d.expected {
+			expected = append(expected, all[e])
+		}
+		if strings.Join(expected, ",") != strings.Join(res, ",") {
+			t.Errorf("Test %d failed. Expected %v got %v", i, expected, res)
+		}
+	}
+}
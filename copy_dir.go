@@ -0,0 +1,63 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// CopyDirOptions configures CopyDir
+type CopyDirOptions struct {
+	// PreserveTimes sets the access and modification times of each copied
+	// file and directory to the modification time of its source. Filesystems
+	// that cannot set times are silently skipped.
+	PreserveTimes bool
+}
+
+// CopyDir recursively copies the directory src to dst, keeping file modes
+func CopyDir(fs afero.Fs, src, dst string, opts CopyDirOptions) error {
+	type dirTime struct {
+		path    string
+		modTime time.Time
+	}
+	var dirs []dirTime
+	err := afero.Walk(fs, src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			perm := info.Mode().Perm()
+			if perm == 0 {
+				perm = 0755
+			}
+			dirs = append(dirs, dirTime{target, info.ModTime()})
+			return fs.MkdirAll(target, perm)
+		}
+		if _, err := CopyFS(fs, path, target); err != nil {
+			return err
+		}
+		if err := fs.Chmod(target, info.Mode().Perm()); err != nil {
+			return err
+		}
+		if opts.PreserveTimes {
+			fs.Chtimes(target, info.ModTime(), info.ModTime())
+		}
+		return nil
+	})
+	if err != nil || !opts.PreserveTimes {
+		return err
+	}
+	// directory times are set last as copying their contents modifies them,
+	// deepest first so setting a child does not disturb its parent
+	for i := len(dirs) - 1; i >= 0; i-- {
+		fs.Chtimes(dirs[i].path, dirs[i].modTime, dirs[i].modTime)
+	}
+	return nil
+}
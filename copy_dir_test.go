@@ -0,0 +1,73 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func TestCopyDir(t *testing.T) {
+	files := []string{"a.txt", "sub/b.txt", "sub/deep/c.txt"}
+	past := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, preserve := range []bool{true, false} {
+		fs := newTestTree("/src", files)
+		for _, f := range files {
+			fs.Chtimes(filepath.Join("/src", f), past, past)
+		}
+		fs.Chmod("/src/sub/b.txt", 0600)
+		start := time.Now().Add(-time.Minute)
+
+		if err := CopyDir(fs, "/src", "/dst", CopyDirOptions{PreserveTimes: preserve}); err != nil {
+			t.Fatal(err)
+		}
+		for i, f := range files {
+			content, err := afero.ReadFile(fs, filepath.Join("/dst", f))
+			if err != nil || string(content) != f {
+				t.Errorf("Test %d failed. Expected %s got %s, %v", i, f, content, err)
+			}
+			info, _ := fs.Stat(filepath.Join("/dst", f))
+			if preserve && !info.ModTime().Equal(past) {
+				t.Errorf("Test %d failed. Expected modtime %s got %s", i, past, info.ModTime())
+			}
+			if !preserve && info.ModTime().Before(start) {
+				t.Errorf("Test %d failed. Expected current modtime got %s", i, info.ModTime())
+			}
+		}
+		if info, _ := fs.Stat("/dst/sub/b.txt"); info.Mode().Perm() != 0600 {
+			t.Errorf("Expected mode to be kept got %s", info.Mode())
+		}
+	}
+}
+
+func TestCopyDirOsFs(t *testing.T) {
+	fs := afero.NewOsFs()
+	dir, err := afero.TempDir(fs, "", "goutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	past := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	fs.MkdirAll(filepath.Join(src, "sub"), 0755)
+	afero.WriteFile(fs, filepath.Join(src, "sub", "a.txt"), []byte("a"), 0644)
+	fs.Chtimes(filepath.Join(src, "sub", "a.txt"), past, past)
+	fs.Chtimes(filepath.Join(src, "sub"), past, past)
+
+	if err := CopyDir(fs, src, dst, CopyDirOptions{PreserveTimes: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{filepath.Join("sub", "a.txt"), "sub"} {
+		info, err := fs.Stat(filepath.Join(dst, p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.ModTime().Equal(past) {
+			t.Errorf("Expected %s modtime %s got %s", p, past, info.ModTime())
+		}
+	}
+}
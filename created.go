@@ -0,0 +1,52 @@
+package goutils
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// ListByCreationTime returns the names of the files in dir ordered from
+// oldest to newest by creation time. Directories and dotfiles are skipped
+// as with ListFiles.
+// Creation (birth) time is only available on darwin and windows, and only
+// for the OsFs. Elsewhere, including linux and in-memory filesystems, the
+// modification time is used instead. Ties are ordered by name.
+func ListByCreationTime(fs afero.Fs, dir string) ([]string, error) {
+	fd, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+	return sortByCreationTime(fd), nil
+}
+
+// sortByCreationTime orders non-dot file names by creation time, falling
+// back to modification time when no creation time is available
+func sortByCreationTime(fd []os.FileInfo) []string {
+	var files []os.FileInfo
+	for _, info := range fd {
+		if isVisibleFile(info) {
+			files = append(files, info)
+		}
+	}
+	created := func(info os.FileInfo) time.Time {
+		if t, ok := birthTime(info); ok {
+			return t
+		}
+		return info.ModTime()
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		ti, tj := created(files[i]), created(files[j])
+		if ti.Equal(tj) {
+			return files[i].Name() < files[j].Name()
+		}
+		return ti.Before(tj)
+	})
+	names := []string{}
+	for _, info := range files {
+		names = append(names, info.Name())
+	}
+	return names
+}
@@ -0,0 +1,60 @@
+package goutils
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func TestSortByCreationTime(t *testing.T) {
+	base := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	fd := []os.FileInfo{
+		fakeFileInfo{name: "c.jpg", modTime: base.Add(time.Hour)},
+		fakeFileInfo{name: "a.jpg", modTime: base.Add(2 * time.Hour)},
+		fakeFileInfo{name: "b.jpg", modTime: base},
+		fakeFileInfo{name: "d.jpg", modTime: base},
+		fakeFileInfo{name: ".hidden", modTime: base.Add(-time.Hour)},
+		fakeFileInfo{name: "album", mode: os.ModeDir, modTime: base.Add(-time.Hour)},
+	}
+	expected := []string{"b.jpg", "d.jpg", "c.jpg", "a.jpg"}
+
+	res := sortByCreationTime(fd)
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %v got %v", expected, res)
+	}
+	for i, e := range expected {
+		if e != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, res[i])
+		}
+	}
+}
+
+func TestListByCreationTime(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	base := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	names := []string{"z.jpg", "y.jpg", "x.jpg"}
+	for i, name := range names {
+		afero.WriteFile(fs, "/gallery/"+name, nil, 0644)
+		created := base.Add(time.Duration(i) * time.Hour)
+		fs.Chtimes("/gallery/"+name, created, created)
+	}
+
+	res, err := ListByCreationTime(fs, "/gallery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(names) {
+		t.Fatalf("Expected %v got %v", names, res)
+	}
+	for i, e := range names {
+		if e != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, res[i])
+		}
+	}
+
+	if _, err := ListByCreationTime(fs, "/missing"); err == nil {
+		t.Error("Expected error for missing directory")
+	}
+}
@@ -0,0 +1,151 @@
+package goutils
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+)
+
+// DeepestCommonDir returns the deepest directory on fs that contains all of
+// paths. Unlike the purely lexical CommonPrefix, the result is trimmed back
+// until it is an existing directory, so a shared file prefix or a missing
+// directory is never returned. The paths must be all absolute or all relative.
+func DeepestCommonDir(fs afero.Fs, paths []string) (string, error) {
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no paths given")
+	}
+	for _, p := range paths[1:] {
+		if filepath.IsAbs(p) != filepath.IsAbs(paths[0]) {
+			return "", fmt.Errorf("cannot mix absolute and relative paths in %v", paths)
+		}
+	}
+	dir := CommonPrefix(paths)
+	if dir == "" {
+		dir = "."
+	}
+	for {
+		exists, err := DirExists(fs, dir)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no common directory exists for %v", paths)
+		}
+		dir = parent
+	}
+}
+
+// ListImmediate reads only the direct children of dir and splits them into
+// directory and file names, skipping dot entries as ListDirNames and
+// ListFiles do.
+func ListImmediate(fs afero.Fs, dir string) (dirs []string, files []string, err error) {
+	fd, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ListDirNames(fd), ListFiles(fd), nil
+}
+
+// DirsToCreate returns the sorted directories that must be created with
+// MkdirAll before the files at paths can be written. Directories that
+// already exist are skipped, as are missing directories that will be
+// created anyway as the ancestor of another.
+func DirsToCreate(fs afero.Fs, paths []string) ([]string, error) {
+	missing := make(map[string]bool)
+	for _, p := range paths {
+		dir := filepath.Dir(filepath.Clean(p))
+		if _, ok := missing[dir]; ok {
+			continue
+		}
+		exists, err := DirExists(fs, dir)
+		if err != nil {
+			return nil, err
+		}
+		missing[dir] = !exists
+	}
+	dirs := []string{}
+	for dir, create := range missing {
+		if create {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	minimal := []string{}
+	for _, dir := range dirs {
+		if !hasDescendant(dir, dirs) {
+			minimal = append(minimal, dir)
+		}
+	}
+	return minimal, nil
+}
+
+// hasDescendant checks if any of dirs is nested below dir
+func hasDescendant(dir string, dirs []string) bool {
+	for _, d := range dirs {
+		if d != dir && isWithin(dir, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListGroupedByDir walks root and maps each directory, relative to root and
+// slash separated, to the names of the files directly within it. The root
+// itself is keyed as "." and dot entries are skipped as in ListImmediate.
+//  {".": ["index.md"], "docs": ["intro.md"], "docs/api": []}
+func ListGroupedByDir(fs afero.Fs, root string) (map[string][]string, error) {
+	grouped := make(map[string][]string)
+	if err := listGroupedByDir(fs, root, ".", grouped); err != nil {
+		return nil, err
+	}
+	return grouped, nil
+}
+
+func listGroupedByDir(fs afero.Fs, root string, rel string, grouped map[string][]string) error {
+	dirs, files, err := ListImmediate(fs, filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	grouped[rel] = files
+	for _, dir := range dirs {
+		if err := listGroupedByDir(fs, root, path.Join(rel, dir), grouped); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsEmptyDir checks if the directory at path has no entries at all,
+// dotfiles included. Unlike IsEmpty only a single entry is read, so it
+// stays cheap for huge directories. A path that is not a directory
+// results in an error.
+func IsEmptyDir(fs afero.Fs, path string) (bool, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	if !info.IsDir() {
+		return false, fmt.Errorf("%s is not a directory", path)
+	}
+	names, err := f.Readdirnames(1)
+	if err == io.EOF {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(names) == 0, nil
+}
@@ -0,0 +1,190 @@
+package goutils
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeepestCommonDir(t *testing.T) {
+	fs := newTestTree("/project", []string{
+		"src/main.go",
+		"src/util/util.go",
+		"src/util/util_test.go",
+		"docs/index.md",
+	})
+	type test struct {
+		input    []string
+		expected string
+	}
+	data := []test{
+		{[]string{"/project/src/main.go", "/project/src/util/util.go"}, "/project/src"},
+		{[]string{"/project/src/util/util.go", "/project/src/util/util_test.go"}, "/project/src/util"},
+		// the lexical prefix is a file
+		{[]string{"/project/src/main.go", "/project/src/main.go"}, "/project/src"},
+		// the lexical prefix does not exist yet
+		{[]string{"/project/src/new/a.go", "/project/src/new/b.go"}, "/project/src"},
+		{[]string{"/project/src/main.go", "/project/docs/index.md"}, "/project"},
+		{[]string{"/project/src/main.go", "/other/file"}, "/"},
+	}
+
+	for i, d := range data {
+		var input []string
+		for _, p := range d.input {
+			input = append(input, filepath.FromSlash(p))
+		}
+		res, err := DeepestCommonDir(fs, input)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if filepath.FromSlash(d.expected) != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+
+	if _, err := DeepestCommonDir(fs, nil); err == nil {
+		t.Error("Expected error for no paths")
+	}
+	abs, _ := filepath.Abs("src")
+	if _, err := DeepestCommonDir(fs, []string{abs, filepath.FromSlash("src/util")}); err == nil {
+		t.Error("Expected error for mixed absolute and relative paths")
+	}
+}
+
+func TestListImmediate(t *testing.T) {
+	fs := newTestTree("/project", []string{
+		"README.md",
+		"main.go",
+		".gitignore",
+		"src/util.go",
+		"src/deep/nested.go",
+		"docs/index.md",
+		".git/config",
+	})
+	expectedDirs := []string{"docs", "src"}
+	expectedFiles := []string{"README.md", "main.go"}
+
+	dirs, files, err := ListImmediate(fs, "/project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != len(expectedDirs) || len(files) != len(expectedFiles) {
+		t.Fatalf("Expected %v and %v got %v and %v", expectedDirs, expectedFiles, dirs, files)
+	}
+	for i, e := range expectedDirs {
+		if e != dirs[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, dirs[i])
+		}
+	}
+	for i, e := range expectedFiles {
+		if e != files[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, files[i])
+		}
+	}
+
+	if _, _, err := ListImmediate(fs, "/missing"); err == nil {
+		t.Error("Expected error for missing directory")
+	}
+}
+
+func TestDirsToCreate(t *testing.T) {
+	fs := newTestTree("/out", []string{"existing/file.txt"})
+	input := []string{
+		"/out/file.txt",
+		"/out/existing/new.txt",
+		"/out/a/b/c/file.txt",
+		"/out/a/b/file.txt",
+		"/out/a/file.txt",
+		"/out/a/b-c/file.txt",
+		"/out/existing/sub/file.txt",
+		"/out/existing/sub/other.txt",
+		"/new/file.txt",
+	}
+	expected := []string{
+		"/new",
+		"/out/a/b-c",
+		"/out/a/b/c",
+		"/out/existing/sub",
+	}
+
+	var paths []string
+	for _, p := range input {
+		paths = append(paths, filepath.FromSlash(p))
+	}
+	res, err := DirsToCreate(fs, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %v got %v", expected, res)
+	}
+	for i, e := range expected {
+		if filepath.FromSlash(e) != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, res[i])
+		}
+	}
+}
+
+func TestListGroupedByDir(t *testing.T) {
+	fs := newTestTree("/site", []string{
+		"index.md",
+		".htaccess",
+		"docs/intro.md",
+		"docs/setup.md",
+		"docs/api/ref.md",
+		"docs/api/v1/old.md",
+		".git/config",
+	})
+	fs.MkdirAll(filepath.FromSlash("/site/assets"), 0755)
+	expected := map[string][]string{
+		".":           {"index.md"},
+		"assets":      {},
+		"docs":        {"intro.md", "setup.md"},
+		"docs/api":    {"ref.md"},
+		"docs/api/v1": {"old.md"},
+	}
+
+	res, err := ListGroupedByDir(fs, "/site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Errorf("Expected %v got %v", expected, res)
+	}
+	for dir, files := range expected {
+		if strings.Join(files, ",") != strings.Join(res[dir], ",") {
+			t.Errorf("Expected %s to hold %v got %v", dir, files, res[dir])
+		}
+	}
+}
+
+func TestIsEmptyDir(t *testing.T) {
+	fs := newTestTree("/data", []string{"full/a.txt", "dotfile/.keep", "file.txt"})
+	fs.MkdirAll(filepath.FromSlash("/data/empty"), 0755)
+	fs.MkdirAll(filepath.FromSlash("/data/nested/empty"), 0755)
+
+	type test struct {
+		input    string
+		expected bool
+		err      bool
+	}
+	data := []test{
+		{"/data/empty", true, false},
+		{"/data/nested/empty", true, false},
+		{"/data/full", false, false},
+		{"/data/dotfile", false, false},
+		{"/data/nested", false, false},
+		{"/data/file.txt", false, true},
+		{"/data/missing", false, true},
+	}
+
+	for i, d := range data {
+		res, err := IsEmptyDir(fs, filepath.FromSlash(d.input))
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+		}
+	}
+}
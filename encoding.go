@@ -0,0 +1,100 @@
+package goutils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/spf13/afero"
+)
+
+// Encoding names returned by DetectBOM
+const (
+	EncodingUTF8    = "UTF-8"
+	EncodingUTF16LE = "UTF-16LE"
+	EncodingUTF16BE = "UTF-16BE"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DetectBOM reads the first bytes of a file and identifies a UTF-8, UTF-16LE
+// or UTF-16BE byte order mark, returning the encoding name and the length of
+// the BOM so it can be skipped. Files without a BOM return an empty encoding
+// and zero length.
+func DetectBOM(fs afero.Fs, path string) (encoding string, bomLen int, err error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	head := make([]byte, len(bomUTF8))
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", 0, err
+	}
+	encoding, bomLen = bomOf(head[:n])
+	return encoding, bomLen, nil
+}
+
+// bomOf identifies the BOM at the start of b
+func bomOf(b []byte) (string, int) {
+	switch {
+	case bytes.HasPrefix(b, bomUTF8):
+		return EncodingUTF8, len(bomUTF8)
+	case bytes.HasPrefix(b, bomUTF16LE):
+		return EncodingUTF16LE, len(bomUTF16LE)
+	case bytes.HasPrefix(b, bomUTF16BE):
+		return EncodingUTF16BE, len(bomUTF16BE)
+	}
+	return "", 0
+}
+
+// ReadFileNoBOM reads the whole file, stripping a leading UTF-8 BOM if present
+func ReadFileNoBOM(fs afero.Fs, path string) ([]byte, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimPrefix(content, bomUTF8), nil
+}
+
+// ReadFileUTF16 reads a file with a UTF-16 BOM and returns its content
+// decoded to UTF-8 without the BOM. Files without a UTF-16 BOM are assumed
+// to already be UTF-8 and are returned unchanged.
+func ReadFileUTF16(fs afero.Fs, path string) ([]byte, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder
+	switch encoding, _ := bomOf(content); encoding {
+	case EncodingUTF16LE:
+		order = binary.LittleEndian
+	case EncodingUTF16BE:
+		order = binary.BigEndian
+	default:
+		return content, nil
+	}
+	content = content[len(bomUTF16LE):]
+	if len(content)%2 != 0 {
+		return nil, fmt.Errorf("%s: truncated UTF-16 content", path)
+	}
+	units := make([]uint16, len(content)/2)
+	for i := range units {
+		units[i] = order.Uint16(content[2*i:])
+	}
+	out := make([]byte, 0, len(units))
+	buf := make([]byte, utf8.UTFMax)
+	for _, r := range utf16.Decode(units) {
+		n := utf8.EncodeRune(buf, r)
+		out = append(out, buf[:n]...)
+	}
+	return out, nil
+}
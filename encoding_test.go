@@ -0,0 +1,100 @@
+package goutils
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestDetectBOM(t *testing.T) {
+	type bom struct {
+		encoding string
+		length   int
+	}
+	type test struct {
+		input    []byte
+		expected bom
+	}
+	data := []test{
+		{[]byte("\xEF\xBB\xBFhello"), bom{EncodingUTF8, 3}},
+		{[]byte("\xFF\xFEh\x00"), bom{EncodingUTF16LE, 2}},
+		{[]byte("\xFE\xFF\x00h"), bom{EncodingUTF16BE, 2}},
+		{[]byte("hello"), bom{"", 0}},
+		{[]byte("\xEF\xBB"), bom{"", 0}},
+		{[]byte{}, bom{"", 0}},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "file.txt", d.input, 0644)
+		encoding, bomLen, err := DetectBOM(fs, "file.txt")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if encoding != d.expected.encoding {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.encoding, encoding)
+		}
+		if bomLen != d.expected.length {
+			t.Errorf("Test %d failed. Expected %d got %d", i, d.expected.length, bomLen)
+		}
+	}
+
+	if _, _, err := DetectBOM(fs, "missing.txt"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestReadFileNoBOM(t *testing.T) {
+	type test struct {
+		input    []byte
+		expected string
+	}
+	data := []test{
+		{[]byte("\xEF\xBB\xBFhello"), "hello"},
+		{[]byte("hello"), "hello"},
+		{[]byte("\xEF\xBB\xBF"), ""},
+		{[]byte{}, ""},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "file.txt", d.input, 0644)
+		res, err := ReadFileNoBOM(fs, "file.txt")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != string(res) {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestReadFileUTF16(t *testing.T) {
+	type test struct {
+		input    []byte
+		expected string
+		err      bool
+	}
+	data := []test{
+		{[]byte("\xFF\xFEh\x00i\x00\xe9\x00"), "hié", false},
+		{[]byte("\xFE\xFF\x00h\x00i\x00\xe9"), "hié", false},
+		// surrogate pair for U+1F600
+		{[]byte("\xFF\xFE=\xd8\x00\xde"), "\U0001F600", false},
+		{[]byte("\xFF\xFE"), "", false},
+		{[]byte("plain é"), "plain é", false},
+		{[]byte("\xEF\xBB\xBFhello"), "\xEF\xBB\xBFhello", false},
+		{[]byte("\xFF\xFEh\x00i"), "", true},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "file.txt", d.input, 0644)
+		res, err := ReadFileUTF16(fs, "file.txt")
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if d.expected != string(res) {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected, res)
+		}
+	}
+}
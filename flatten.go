@@ -0,0 +1,51 @@
+package goutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// Flatten copies every file below srcRoot into dstDir, encoding the relative
+// path of each file into its name by joining the path components with sep
+//  a/b/c.png --> a_b_c.png
+// The returned map goes from flattened name to original relative path.
+// If two files would flatten to the same name an error is returned before
+// anything is copied.
+func Flatten(src afero.Fs, srcRoot string, dst afero.Fs, dstDir string, sep string) (map[string]string, error) {
+	flattened := make(map[string]string)
+	err := afero.Walk(src, srcRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(srcRoot, path)
+		if err != nil {
+			return err
+		}
+		name := strings.Join(splitComponents(rel), sep)
+		if existing, ok := flattened[name]; ok {
+			return fmt.Errorf("%s and %s both flatten to %s", existing, rel, name)
+		}
+		flattened[name] = rel
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dst.MkdirAll(dstDir, 0755); err != nil {
+		return nil, err
+	}
+	for name, rel := range flattened {
+		if _, err := copyBetweenFs(src, filepath.Join(srcRoot, rel), dst, filepath.Join(dstDir, name)); err != nil {
+			return nil, err
+		}
+	}
+	return flattened, nil
+}
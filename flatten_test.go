@@ -0,0 +1,57 @@
+package goutils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestFlatten(t *testing.T) {
+	src := newTestTree("/assets", []string{
+		"a/b/c.png",
+		"a/d.png",
+		"e.png",
+	})
+	expected := map[string]string{
+		"a_b_c.png": "a/b/c.png",
+		"a_d.png":   "a/d.png",
+		"e.png":     "e.png",
+	}
+
+	dst := afero.NewMemMapFs()
+	res, err := Flatten(src, "/assets", dst, "/bundle", "_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Errorf("Expected %v got %v", expected, res)
+	}
+	for name, rel := range expected {
+		if res[name] != filepath.FromSlash(rel) {
+			t.Errorf("Expected %s to map to %s got %s", name, rel, res[name])
+		}
+		content, err := afero.ReadFile(dst, filepath.Join("/bundle", name))
+		if err != nil {
+			t.Errorf("Expected %s to be copied: %s", name, err)
+			continue
+		}
+		if string(content) != rel {
+			t.Errorf("Expected %s to contain %s got %s", name, rel, content)
+		}
+	}
+}
+
+func TestFlattenCollision(t *testing.T) {
+	src := newTestTree("/assets", []string{
+		"a/b_c.png",
+		"a_b/c.png",
+	})
+	dst := afero.NewMemMapFs()
+	if _, err := Flatten(src, "/assets", dst, "/bundle", "_"); err == nil {
+		t.Error("Expected collision error")
+	}
+	if exists, _ := Exists(dst, "/bundle"); exists {
+		t.Error("Expected nothing to be copied on collision")
+	}
+}
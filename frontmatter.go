@@ -0,0 +1,82 @@
+package goutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/afero"
+)
+
+// Front matter formats returned by SplitFrontMatter
+const (
+	FrontMatterYAML = "yaml"
+	FrontMatterTOML = "toml"
+	FrontMatterJSON = "json"
+)
+
+// SplitFrontMatter reads a content file and splits off any front matter at
+// its top, returning the front matter, the remaining body and the format.
+// YAML front matter is delimited by "---" lines, TOML by "+++" lines, and
+// JSON is an object starting at the first byte of the file. The delimiters
+// are not included in front, the braces of a JSON object are.
+// A file without front matter returns an empty front, the whole content as
+// the body and an empty format.
+func SplitFrontMatter(fs afero.Fs, path string) (front []byte, body []byte, format string, err error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	content = bytes.TrimPrefix(content, bomUTF8)
+	if bytes.HasPrefix(content, []byte("{")) {
+		return splitJSONFrontMatter(content)
+	}
+
+	first, rest := splitLine(content)
+	var delim string
+	switch string(bytes.TrimRight(first, " \t\r")) {
+	case "---":
+		delim, format = "---", FrontMatterYAML
+	case "+++":
+		delim, format = "+++", FrontMatterTOML
+	default:
+		return []byte{}, content, "", nil
+	}
+	start := len(content) - len(rest)
+	for pos := start; pos < len(content); {
+		line, next := splitLine(content[pos:])
+		if string(bytes.TrimRight(line, " \t\r")) == delim {
+			return content[start:pos], next, format, nil
+		}
+		pos = len(content) - len(next)
+	}
+	return nil, nil, "", fmt.Errorf("unterminated %s front matter in %s", format, path)
+}
+
+// splitJSONFrontMatter splits the leading JSON object from content
+func splitJSONFrontMatter(content []byte) ([]byte, []byte, string, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return nil, nil, "", fmt.Errorf("invalid json front matter: %s", err)
+	}
+	body := content[dec.InputOffset():]
+	body = bytes.TrimPrefix(bytes.TrimPrefix(body, []byte("\r")), []byte("\n"))
+	return content[:dec.InputOffset()], body, FrontMatterJSON, nil
+}
+
+// splitLine returns the first line of b, without its newline, and the rest
+func splitLine(b []byte) (line []byte, rest []byte) {
+	i := bytes.IndexByte(b, '\n')
+	if i < 0 {
+		return b, b[len(b):]
+	}
+	return b[:i], b[i+1:]
+}
+
+// StripFrontMatter returns the body of a content file with any front
+// matter, as found by SplitFrontMatter, removed.
+func StripFrontMatter(fs afero.Fs, path string) ([]byte, error) {
+	_, body, _, err := SplitFrontMatter(fs, path)
+	return body, err
+}
@@ -0,0 +1,112 @@
+package goutils
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	type split struct {
+		front  string
+		body   string
+		format string
+	}
+	type test struct {
+		input    string
+		expected split
+	}
+	data := []test{
+		{
+			"---\ntitle: Hello\ntags: [a, b]\n---\n# Body\n\ntext\n",
+			split{"title: Hello\ntags: [a, b]\n", "# Body\n\ntext\n", FrontMatterYAML},
+		},
+		{
+			"---\r\ntitle: Hello\r\n---\r\nbody\r\n",
+			split{"title: Hello\r\n", "body\r\n", FrontMatterYAML},
+		},
+		{
+			"+++\ntitle = \"Hello\"\n+++\nbody\n",
+			split{"title = \"Hello\"\n", "body\n", FrontMatterTOML},
+		},
+		{
+			"{\n  \"title\": \"Hello {world}\"\n}\nbody\n",
+			split{"{\n  \"title\": \"Hello {world}\"\n}", "body\n", FrontMatterJSON},
+		},
+		{
+			"---\n---\nbody",
+			split{"", "body", FrontMatterYAML},
+		},
+		{
+			"---\ntitle: Hello\n---",
+			split{"title: Hello\n", "", FrontMatterYAML},
+		},
+		{
+			"\xEF\xBB\xBF+++\ntitle = 1\n+++\nbody",
+			split{"title = 1\n", "body", FrontMatterTOML},
+		},
+		{
+			"# Just a body\n---\nwith a rule\n",
+			split{"", "# Just a body\n---\nwith a rule\n", ""},
+		},
+		{
+			"",
+			split{"", "", ""},
+		},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "page.md", []byte(d.input), 0644)
+		front, body, format, err := SplitFrontMatter(fs, "page.md")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected.front != string(front) {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected.front, front)
+		}
+		if d.expected.body != string(body) {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected.body, body)
+		}
+		if d.expected.format != format {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.format, format)
+		}
+	}
+
+	for i, input := range []string{"---\ntitle: Hello\n", "{\"title\": \"Hello\"\nbody"} {
+		afero.WriteFile(fs, "page.md", []byte(input), 0644)
+		if _, _, _, err := SplitFrontMatter(fs, "page.md"); err == nil {
+			t.Errorf("Test %d failed. Expected error for unterminated front matter", i)
+		}
+	}
+}
+
+func TestStripFrontMatter(t *testing.T) {
+	body := "# Title\n\nSome *markdown* text.\n---\nwith a rule\n"
+	type test struct {
+		input    string
+		expected string
+	}
+	data := []test{
+		{"---\ntitle: Hello\n---\n" + body, body},
+		{"+++\ntitle = \"Hello\"\n+++\n" + body, body},
+		{"{\"title\": \"Hello\"}\n" + body, body},
+		{body, body},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "page.md", []byte(d.input), 0644)
+		res, err := StripFrontMatter(fs, "page.md")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != string(res) {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected, res)
+		}
+	}
+
+	if _, err := StripFrontMatter(fs, "missing.md"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -0,0 +1,150 @@
+package goutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/afero"
+)
+
+// IsGlob checks if path contains any unescaped glob metacharacters *, ? or [
+// so it should be expanded rather than opened directly.
+// Outside of windows, where backslash is the path separator, a
+// metacharacter escaped with a backslash such as \* is taken literally.
+func IsGlob(path string) bool {
+	escapes := runtime.GOOS != "windows"
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case '*', '?', '[':
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandOrLiteral expands arg with afero.Glob if it is a glob, as checked by
+// IsGlob, returning an error if nothing matches. Otherwise arg is returned
+// as is, the same way a shell passes arguments.
+func ExpandOrLiteral(fs afero.Fs, arg string) ([]string, error) {
+	if !IsGlob(arg) {
+		return []string{arg}, nil
+	}
+	matches, err := afero.Glob(fs, arg)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no matches found for %s", arg)
+	}
+	return matches, nil
+}
+
+// GlobExplain splits a slash separated glob pattern into its segments,
+// validating the syntax of each, and reports whether any segment is the **
+// wildcard that matches across directories. An absolute pattern begins
+// with an empty segment, as with a split path.
+//  src/**/*.go --> [src ** *.go], true
+func GlobExplain(pattern string) (segments []string, hasDoubleStar bool, err error) {
+	for i, seg := range splitComponents(pattern) {
+		if seg == "" && i > 0 {
+			continue
+		}
+		if seg == "**" {
+			hasDoubleStar = true
+		} else if _, err := filepath.Match(seg, ""); err != nil {
+			return nil, false, fmt.Errorf("invalid segment %q of %s: %s", seg, pattern, err)
+		}
+		segments = append(segments, seg)
+	}
+	return segments, hasDoubleStar, nil
+}
+
+// GlobStar is the same as afero.Glob but a ** segment also matches any
+// number of directories, including none, so src/**/*.go finds Go files at
+// every depth below src. Matches are returned in walk order.
+func GlobStar(fs afero.Fs, pattern string) ([]string, error) {
+	segments, hasDoubleStar, err := GlobExplain(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if !hasDoubleStar {
+		return afero.Glob(fs, pattern)
+	}
+	// walk from the deepest directory without metacharacters
+	n := 0
+	for n < len(segments) && !IsGlob(segments[n]) && segments[n] != "**" {
+		n++
+	}
+	base := joinComponents(segments[:n])
+	switch {
+	case n == 1 && segments[0] == "":
+		base = FilePathSeparator
+	case base == "":
+		base = "."
+	}
+	var matches []string
+	err = afero.Walk(fs, base, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// unreadable directories are skipped, as afero.Glob ignores I/O errors
+			return nil
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if matchSegments(segments[n:], splitComponents(rel)) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// matchSegments matches path components against pattern segments, where a
+// ** segment consumes any number of components
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		return matchSegments(pattern[1:], parts) || (len(parts) > 0 && matchSegments(pattern, parts[1:]))
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	ok, _ := filepath.Match(pattern[0], parts[0])
+	return ok && matchSegments(pattern[1:], parts[1:])
+}
+
+// GlobDiff evaluates pattern with GlobStar and compares the matches to the
+// previous ones, returning the paths newly matched, those no longer matched
+// and the current matches, for reacting only to relevant changes.
+func GlobDiff(fs afero.Fs, pattern string, previous []string) (added, removed []string, current []string, err error) {
+	current, err = GlobStar(fs, pattern)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	before := make(map[string]bool, len(previous))
+	for _, p := range previous {
+		before[p] = true
+	}
+	now := make(map[string]bool, len(current))
+	for _, p := range current {
+		now[p] = true
+		if !before[p] {
+			added = append(added, p)
+		}
+	}
+	for _, p := range previous {
+		if !now[p] {
+			removed = append(removed, p)
+		}
+	}
+	return added, removed, current, nil
+}
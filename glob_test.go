@@ -0,0 +1,223 @@
+package goutils
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestIsGlob(t *testing.T) {
+	type test struct {
+		input    string
+		expected bool
+	}
+	data := []test{
+		{"run001.mod", false},
+		{"path/to/run001.mod", false},
+		{"", false},
+		{"*.mod", true},
+		{"run00?.mod", true},
+		{"run[0-9].mod", true},
+		{"path/*/run.mod", true},
+		{"run]1.mod", false},
+	}
+	if runtime.GOOS != "windows" {
+		data = append(data,
+			test{`run\*.mod`, false},
+			test{`run\?\[1].mod`, false},
+			test{`run\\*.mod`, true},
+			test{`run\*.*`, true},
+		)
+	}
+
+	for i, d := range data {
+		res := IsGlob(d.input)
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %v got %v for %s", i, d.expected, res, d.input)
+		}
+	}
+}
+
+func TestExpandOrLiteral(t *testing.T) {
+	fs := newTestTree("/models", []string{
+		"run001.mod",
+		"run002.mod",
+		"run002.lst",
+	})
+	type test struct {
+		input    string
+		expected []string
+		err      bool
+	}
+	data := []test{
+		{"/models/*.mod", []string{"/models/run001.mod", "/models/run002.mod"}, false},
+		{"/models/run00[2].*", []string{"/models/run002.lst", "/models/run002.mod"}, false},
+		{"/models/*.csv", nil, true},
+		{"/models/run001.mod", []string{"/models/run001.mod"}, false},
+		{"/models/missing.mod", []string{"/models/missing.mod"}, false},
+	}
+
+	for i, d := range data {
+		res, err := ExpandOrLiteral(fs, filepath.FromSlash(d.input))
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if len(res) != len(d.expected) {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+			continue
+		}
+		for j, e := range d.expected {
+			if filepath.FromSlash(e) != res[j] {
+				t.Errorf("Test %d failed. Expected %s got %s", i, e, res[j])
+			}
+		}
+	}
+}
+
+func TestGlobExplain(t *testing.T) {
+	type explained struct {
+		segments      []string
+		hasDoubleStar bool
+	}
+	type test struct {
+		input    string
+		expected explained
+		err      bool
+	}
+	data := []test{
+		{"*.go", explained{[]string{"*.go"}, false}, false},
+		{"src/pkg/*.go", explained{[]string{"src", "pkg", "*.go"}, false}, false},
+		{"src/**/*.go", explained{[]string{"src", "**", "*.go"}, true}, false},
+		{"**", explained{[]string{"**"}, true}, false},
+		{"/etc/app/[a-z]?.conf", explained{[]string{"", "etc", "app", "[a-z]?.conf"}, false}, false},
+		{"docs//*.md/", explained{[]string{"docs", "*.md"}, false}, false},
+		// ** only crosses directories as a whole segment
+		{"src/a**b", explained{[]string{"src", "a**b"}, false}, false},
+		{"src/[a-z.go", explained{nil, false}, true},
+		{"src/**/[]x", explained{nil, false}, true},
+		{"*/[", explained{nil, false}, true},
+	}
+
+	for i, d := range data {
+		segments, hasDoubleStar, err := GlobExplain(d.input)
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if strings.Join(d.expected.segments, "|") != strings.Join(segments, "|") || len(d.expected.segments) != len(segments) {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected.segments, segments)
+		}
+		if d.expected.hasDoubleStar != hasDoubleStar {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected.hasDoubleStar, hasDoubleStar)
+		}
+	}
+}
+
+func TestGlobStar(t *testing.T) {
+	fs := newTestTree("/src", []string{
+		"main.go",
+		"README.md",
+		"pkg/util.go",
+		"pkg/util_test.go",
+		"pkg/deep/nested/model.go",
+		"pkg/deep/notes.txt",
+		"cmd/tool/main.go",
+		"vendor/lib/lib.go",
+	})
+
+	type test struct {
+		pattern  string
+		expected []string
+	}
+	data := []test{
+		{"/src/**/*.go", []string{
+			"/src/cmd/tool/main.go",
+			"/src/main.go",
+			"/src/pkg/deep/nested/model.go",
+			"/src/pkg/util.go",
+			"/src/pkg/util_test.go",
+			"/src/vendor/lib/lib.go",
+		}},
+		{"/src/pkg/**/*.go", []string{"/src/pkg/deep/nested/model.go", "/src/pkg/util.go", "/src/pkg/util_test.go"}},
+		{"/src/**/*_test.go", []string{"/src/pkg/util_test.go"}},
+		{"/src/*/**/main.go", []string{"/src/cmd/tool/main.go"}},
+		{"/src/**/deep/**", []string{"/src/pkg/deep", "/src/pkg/deep/nested", "/src/pkg/deep/nested/model.go", "/src/pkg/deep/notes.txt"}},
+		{"/src/**/nested", []string{"/src/pkg/deep/nested"}},
+		// without ** the pattern behaves as afero.Glob
+		{"/src/*/*.go", []string{"/src/pkg/util.go", "/src/pkg/util_test.go"}},
+		{"/src/**/*.rs", nil},
+		{"/missing/**/*.go", nil},
+	}
+
+	for i, d := range data {
+		res, err := GlobStar(fs, filepath.FromSlash(d.pattern))
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		var expected []string
+		for _, e := range d.expected {
+			expected = append(expected, filepath.FromSlash(e))
+		}
+		if strings.Join(expected, ",") != strings.Join(res, ",") {
+			t.Errorf("Test %d failed. Expected %v got %v", i, expected, res)
+		}
+	}
+
+	if _, err := GlobStar(fs, "/src/**/[a-z.go"); err == nil {
+		t.Error("Expected error for malformed pattern")
+	}
+}
+
+func TestGlobDiff(t *testing.T) {
+	fs := newTestTree("/src", []string{"main.go", "pkg/util.go", "README.md"})
+	pattern := filepath.FromSlash("/src/**/*.go")
+	join := func(paths []string) string {
+		return filepath.ToSlash(strings.Join(paths, ","))
+	}
+
+	added, removed, current, err := GlobDiff(fs, pattern, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if join(added) != "/src/main.go,/src/pkg/util.go" || len(removed) != 0 {
+		t.Errorf("Expected everything to be added got %v, %v", added, removed)
+	}
+
+	type test struct {
+		mutate  func(fs afero.Fs)
+		added   string
+		removed string
+	}
+	data := []test{
+		{func(fs afero.Fs) {}, "", ""},
+		{func(fs afero.Fs) { afero.WriteFile(fs, "/src/pkg/deep/new.go", nil, 0644) }, "/src/pkg/deep/new.go", ""},
+		{func(fs afero.Fs) { fs.Remove("/src/main.go") }, "", "/src/main.go"},
+		{
+			func(fs afero.Fs) {
+				fs.Rename("/src/pkg/util.go", "/src/pkg/helpers.go")
+				afero.WriteFile(fs, "/src/notes.md", nil, 0644)
+			},
+			"/src/pkg/helpers.go",
+			"/src/pkg/util.go",
+		},
+	}
+	for i, d := range data {
+		d.mutate(fs)
+		previous := current
+		added, removed, current, err = GlobDiff(fs, pattern, previous)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.added != join(added) {
+			t.Errorf("Test %d failed. Expected added %s got %v", i, d.added, added)
+		}
+		if d.removed != join(removed) {
+			t.Errorf("Test %d failed. Expected removed %s got %v", i, d.removed, removed)
+		}
+	}
+	if join(current) != "/src/pkg/deep/new.go,/src/pkg/helpers.go" {
+		t.Errorf("Expected current matches got %v", current)
+	}
+}
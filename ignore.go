@@ -0,0 +1,181 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// IgnoreMatcher matches slash separated paths, relative to the root the
+// patterns apply to, against a list of .gitignore style patterns
+type IgnoreMatcher struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// NewIgnoreMatcher builds a matcher from gitignore style patterns.
+// A * or ? does not match /, while ** matches across directories.
+// A leading or inner / anchors the pattern to the root, otherwise it matches
+// at any depth. A trailing / only matches directories and a leading !
+// re-includes paths excluded by an earlier pattern. Blank patterns are ignored.
+//  NewIgnoreMatcher([]string{"*.log", "!keep.log", "/build/", "docs/**/*.tmp"})
+func NewIgnoreMatcher(patterns []string) *IgnoreMatcher {
+	m := &IgnoreMatcher{}
+	for _, p := range patterns {
+		if rule, ok := compileIgnorePattern(p); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+	return m
+}
+
+// LoadIgnoreFile reads a .gitignore style file and builds a matcher from its
+// patterns. Blank lines and lines starting with # are skipped, a pattern
+// starting with a literal # can be escaped as \#.
+func LoadIgnoreFile(fs afero.Fs, path string) (*IgnoreMatcher, error) {
+	lines, err := ReadLinesFS(fs, path)
+	if err != nil {
+		return nil, err
+	}
+	var patterns []string
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return NewIgnoreMatcher(patterns), nil
+}
+
+// Match checks if path is ignored. Directories are identified by a trailing
+// slash, as in "build/". A path is also ignored when any of its parent
+// directories is, since git never re-includes files below an excluded
+// directory.
+func (m *IgnoreMatcher) Match(path string) bool {
+	path = filepath.ToSlash(path)
+	isDir := strings.HasSuffix(path, "/")
+	path = strings.TrimPrefix(strings.Trim(path, "/"), "./")
+	if path == "" || path == "." {
+		return false
+	}
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.matchEntry(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.matchEntry(path, isDir)
+}
+
+// matchEntry applies the rules to a single entry, the last matching rule wins
+func (m *IgnoreMatcher) matchEntry(path string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.re.MatchString(path) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+func compileIgnorePattern(p string) (ignoreRule, bool) {
+	var rule ignoreRule
+	p = strings.TrimRight(p, " ")
+	if strings.HasPrefix(p, "!") {
+		rule.negate = true
+		p = p[1:]
+	} else if strings.HasPrefix(p, `\!`) || strings.HasPrefix(p, `\#`) {
+		p = p[1:]
+	}
+	if strings.HasSuffix(p, "/") {
+		rule.dirOnly = true
+		p = strings.TrimRight(p, "/")
+	}
+	if p == "" {
+		return rule, false
+	}
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var re strings.Builder
+	re.WriteString("^")
+	if !anchored && !strings.HasPrefix(p, "**") {
+		re.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(p[i+1:], ']')
+			if end < 0 {
+				re.WriteString(`\[`)
+				continue
+			}
+			class := p[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(p):
+			i++
+			re.WriteString(regexp.QuoteMeta(string(p[i])))
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		// an unusable character class can never match in git either
+		return rule, false
+	}
+	rule.re = compiled
+	return rule, true
+}
+
+// WalkRespectingIgnore walks root like afero.Walk, calling fn for each path
+// not ignored by matcher. Paths are matched relative to root and ignored
+// directories are pruned, so nothing below them is read.
+func WalkRespectingIgnore(fs afero.Fs, root string, matcher *IgnoreMatcher, fn func(path string, info os.FileInfo) error) error {
+	return afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel != "." {
+			rel = filepath.ToSlash(rel)
+			if info.IsDir() && matcher.Match(rel+"/") {
+				return filepath.SkipDir
+			}
+			if !info.IsDir() && matcher.Match(rel) {
+				return nil
+			}
+		}
+		return fn(path, info)
+	})
+}
@@ -0,0 +1,159 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	type test struct {
+		patterns []string
+		path     string
+		expected bool
+	}
+	data := []test{
+		// * does not cross directories, unanchored patterns match at any depth
+		{[]string{"*.log"}, "debug.log", true},
+		{[]string{"*.log"}, "logs/deep/debug.log", true},
+		{[]string{"*.log"}, "debug.log.txt", false},
+		{[]string{"logs/*.log"}, "logs/debug.log", true},
+		{[]string{"logs/*.log"}, "logs/deep/debug.log", false},
+		{[]string{"debug.?og"}, "debug.log", true},
+		{[]string{"debug[0-9].log"}, "debug1.log", true},
+		{[]string{"debug[!0-9].log"}, "debug1.log", false},
+		// ** crosses directories
+		{[]string{"**/temp"}, "temp", true},
+		{[]string{"**/temp"}, "a/b/temp", true},
+		{[]string{"docs/**/*.tmp"}, "docs/a.tmp", true},
+		{[]string{"docs/**/*.tmp"}, "docs/a/b/c.tmp", true},
+		{[]string{"docs/**/*.tmp"}, "other/docs/a.tmp", false},
+		{[]string{"cache/**"}, "cache/a/b.txt", true},
+		{[]string{"cache/**"}, "cache/", false},
+		// a leading slash anchors to the root
+		{[]string{"/build"}, "build", true},
+		{[]string{"/build"}, "src/build", false},
+		{[]string{"build"}, "src/build", true},
+		// a trailing slash only matches directories, and their contents
+		{[]string{"build/"}, "build/", true},
+		{[]string{"build/"}, "build", false},
+		{[]string{"build/"}, "build/out/app.bin", true},
+		{[]string{"build/"}, "src/build/app.bin", true},
+		// negation re-includes, the last matching pattern wins
+		{[]string{"*.log", "!keep.log"}, "keep.log", false},
+		{[]string{"*.log", "!keep.log"}, "other.log", true},
+		{[]string{"!keep.log", "*.log"}, "keep.log", true},
+		{[]string{"*.log", "!keep.log", "keep.log"}, "keep.log", true},
+		// files below an excluded directory cannot be re-included
+		{[]string{"build/", "!build/keep.txt"}, "build/keep.txt", true},
+		{[]string{"build/*", "!build/keep.txt"}, "build/keep.txt", false},
+		// escapes and blank patterns
+		{[]string{`\!important`}, "!important", true},
+		{[]string{"", "   "}, "anything", false},
+		{[]string{"*.log"}, "./debug.log", true},
+	}
+
+	for i, d := range data {
+		res := NewIgnoreMatcher(d.patterns).Match(d.path)
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s to be %v with %v", i, d.path, d.expected, d.patterns)
+		}
+	}
+}
+
+func TestLoadIgnoreFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, ".gitignore", []byte(`# build output
+/build/
+
+*.log
+   
+# keep the release notes
+!release.log
+\#notes.txt
+`), 0644)
+
+	m, err := LoadIgnoreFile(fs, ".gitignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type test struct {
+		input    string
+		expected bool
+	}
+	data := []test{
+		{"build/", true},
+		{"build/app.bin", true},
+		{"src/build/", false},
+		{"debug.log", true},
+		{"release.log", false},
+		{"#notes.txt", true},
+		{"# build output", false},
+		{"notes.txt", false},
+	}
+	for i, d := range data {
+		res := m.Match(d.input)
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s to be %v got %v", i, d.input, d.expected, res)
+		}
+	}
+
+	if _, err := LoadIgnoreFile(fs, "missing"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+// visitRecordingFs records every directory opened for reading
+type visitRecordingFs struct {
+	afero.Fs
+	opened map[string]bool
+}
+
+func (fs visitRecordingFs) Open(name string) (afero.File, error) {
+	fs.opened[filepath.ToSlash(name)] = true
+	return fs.Fs.Open(name)
+}
+
+func TestWalkRespectingIgnore(t *testing.T) {
+	base := newTestTree("/repo", []string{
+		"main.go",
+		"debug.log",
+		"keep.log",
+		"build/app.bin",
+		"build/deep/obj.o",
+		"src/util.go",
+		"src/build/generated.go",
+		"node_modules/pkg/index.js",
+	})
+	fs := visitRecordingFs{base, make(map[string]bool)}
+	matcher := NewIgnoreMatcher([]string{"*.log", "!keep.log", "/build/", "node_modules/"})
+	expected := []string{
+		"/repo",
+		"/repo/keep.log",
+		"/repo/main.go",
+		"/repo/src",
+		"/repo/src/build",
+		"/repo/src/build/generated.go",
+		"/repo/src/util.go",
+	}
+
+	var res []string
+	err := WalkRespectingIgnore(fs, filepath.FromSlash("/repo"), matcher, func(path string, info os.FileInfo) error {
+		res = append(res, filepath.ToSlash(path))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(expected, ",") != strings.Join(res, ",") {
+		t.Errorf("Expected %v got %v", expected, res)
+	}
+	for _, pruned := range []string{"/repo/build", "/repo/build/deep", "/repo/node_modules", "/repo/node_modules/pkg"} {
+		if fs.opened[pruned] {
+			t.Errorf("Expected %s to never be read", pruned)
+		}
+	}
+}
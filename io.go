@@ -2,9 +2,14 @@ package goutils
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/afero"
 )
@@ -63,6 +68,52 @@ func CopyFS(fs afero.Fs, src, dst string) (int64, error) {
 	return nBytes, err
 }
 
+// copyBetweenFs copies a file from one filesystem to another
+// returning number of bytes copied and error
+func copyBetweenFs(srcFs afero.Fs, src string, dstFs afero.Fs, dst string) (int64, error) {
+	source, err := srcFs.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	destination, err := dstFs.Create(dst)
+	if err != nil {
+		return 0, err
+	}
+	defer destination.Close()
+	return io.Copy(destination, source)
+}
+
+// ErrFileTooLarge is returned by ReadFileLimit when a file exceeds the limit
+var ErrFileTooLarge = errors.New("file exceeds size limit")
+
+// ReadFileLimit reads the file at path, returning ErrFileTooLarge instead if
+// it is larger than max bytes. The size is checked before reading, and no
+// more than max bytes are read in case the file grows in the meantime.
+func ReadFileLimit(fs afero.Fs, path string, max int64) ([]byte, error) {
+	inFile, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer inFile.Close()
+	info, err := inFile.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > max {
+		return nil, ErrFileTooLarge
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(inFile, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > max {
+		return nil, ErrFileTooLarge
+	}
+	return content, nil
+}
+
 //ReadLines reads lines for a file at a given path
 func ReadLines(path string) ([]string, error) {
 	inFile, err := os.Open(path)
@@ -95,6 +146,107 @@ func ReadLinesFS(fs afero.Fs, path string) ([]string, error) {
 	return lines, nil
 }
 
+// CountLines counts the lines in a file by reading it in chunks rather than
+// loading it into memory. A final line without a trailing newline is counted.
+func CountLines(fs afero.Fs, path string) (int, error) {
+	inFile, err := fs.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer inFile.Close()
+	buf := make([]byte, 32*1024)
+	count := 0
+	var last byte = '\n'
+	for {
+		n, err := inFile.Read(buf)
+		if n > 0 {
+			count += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if last != '\n' {
+		count++
+	}
+	return count, nil
+}
+
+// TailLines returns the last n lines of a file, or all lines if there are
+// fewer than n. The file is read backwards from the end in blocks so large
+// files are not read in full, unless the file does not support seeking in
+// which case it is scanned from the start.
+func TailLines(fs afero.Fs, path string, n int) ([]string, error) {
+	inFile, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer inFile.Close()
+	if n <= 0 {
+		return []string{}, nil
+	}
+	pos, err := inFile.Seek(0, io.SeekEnd)
+	if err != nil {
+		return tailLinesScan(inFile, n)
+	}
+
+	const blockSize = 4096
+	var data []byte
+	trailing := 0
+	for pos > 0 {
+		size := int64(blockSize)
+		if pos < size {
+			size = pos
+		}
+		pos -= size
+		if _, err := inFile.Seek(pos, io.SeekStart); err != nil {
+			return nil, err
+		}
+		block := make([]byte, size)
+		if _, err := io.ReadFull(inFile, block); err != nil {
+			return nil, err
+		}
+		if data == nil && bytes.HasSuffix(block, []byte{'\n'}) {
+			trailing = 1
+		}
+		data = append(block, data...)
+		// n complete lines are available once n separators precede the end
+		if bytes.Count(data, []byte{'\n'})-trailing >= n {
+			break
+		}
+	}
+	if len(data) == 0 {
+		return []string{}, nil
+	}
+
+	lines := strings.Split(string(data[:len(data)-trailing]), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	return lines, nil
+}
+
+// tailLinesScan keeps the last n lines while scanning r from the start
+func tailLinesScan(r io.Reader, n int) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanLines)
+	lines := []string{}
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
 //WriteLines writes lines to a file at a given path given a filesystem
 func WriteLines(lines []string, path string) error {
 	file, err := os.Create(path)
@@ -124,3 +276,94 @@ func WriteLinesFS(fs afero.Fs, lines []string, path string) error {
 	}
 	return w.Flush()
 }
+
+// PrependFile inserts data at the start of the file at path, creating the
+// file if it does not exist. The result is streamed into a temporary file
+// which then replaces the original, so large files are not read into memory.
+func PrependFile(fs afero.Fs, path string, data []byte) error {
+	return writeAtomic(fs, path, func(w io.Writer) error {
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		original, err := fs.Open(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		defer original.Close()
+		_, err = io.Copy(w, original)
+		return err
+	})
+}
+
+// AppendCapped appends data to the file at path, creating it if needed. If
+// the file would grow beyond maxSize bytes the oldest bytes are dropped from
+// the front, keeping the most recent maxSize. The file is replaced
+// atomically, so a reader never sees it half rewritten.
+func AppendCapped(fs afero.Fs, path string, data []byte, maxSize int64) error {
+	if maxSize <= 0 {
+		return fmt.Errorf("max size must be positive, got %d", maxSize)
+	}
+	var size int64
+	if info, err := fs.Stat(path); err == nil {
+		size = info.Size()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	drop := size + int64(len(data)) - maxSize
+	return writeAtomic(fs, path, func(w io.Writer) error {
+		if drop >= size {
+			_, err := w.Write(data[drop-size:])
+			return err
+		}
+		if size > 0 {
+			original, err := fs.Open(path)
+			if err != nil {
+				return err
+			}
+			defer original.Close()
+			if drop > 0 {
+				if _, err := io.CopyN(ioutil.Discard, original, drop); err != nil {
+					return err
+				}
+			}
+			if _, err := io.Copy(w, original); err != nil {
+				return err
+			}
+		}
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeAtomic calls write with a temporary file in the same directory as
+// path, then renames it over path so readers never see a partial file.
+// The mode of an existing file at path is kept.
+func writeAtomic(fs afero.Fs, path string, write func(w io.Writer) error) (err error) {
+	tmp, err := afero.TempFile(fs, filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			fs.Remove(tmp.Name())
+		}
+	}()
+	if err = write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if info, statErr := fs.Stat(path); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+	if err = fs.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return fs.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,211 @@
+package goutils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestCountLines(t *testing.T) {
+	type test struct {
+		input    string
+		expected int
+	}
+	data := []test{
+		{"", 0},
+		{"one", 1},
+		{"one\n", 1},
+		{"one\ntwo", 2},
+		{"one\r\ntwo\r\n", 2},
+		{"\n\n\n", 3},
+		{strings.Repeat("a line of text\n", 100000), 100000},
+		{strings.Repeat("a line of text\n", 100000) + "last", 100001},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "file.txt", []byte(d.input), 0644)
+		res, err := CountLines(fs, "file.txt")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %d got %d", i, d.expected, res)
+		}
+	}
+
+	if _, err := CountLines(fs, "missing.txt"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+// noSeekFs wraps a filesystem so opened files refuse to seek
+type noSeekFs struct {
+	afero.Fs
+}
+
+type noSeekFile struct {
+	afero.File
+}
+
+func (fs noSeekFs) Open(name string) (afero.File, error) {
+	f, err := fs.Fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return noSeekFile{f}, nil
+}
+
+func (f noSeekFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, errors.New("seek not supported")
+}
+
+func TestTailLines(t *testing.T) {
+	var large []string
+	for i := 0; i < 10000; i++ {
+		large = append(large, fmt.Sprintf("line %d", i))
+	}
+	type test struct {
+		input    string
+		n        int
+		expected []string
+	}
+	data := []test{
+		{"", 3, []string{}},
+		{"one\ntwo\nthree\n", 2, []string{"two", "three"}},
+		{"one\ntwo\nthree", 2, []string{"two", "three"}},
+		{"one\ntwo\n", 5, []string{"one", "two"}},
+		{"one\r\ntwo\r\nthree\r\n", 2, []string{"two", "three"}},
+		{"one\n\nthree\n", 2, []string{"", "three"}},
+		{"one\ntwo\n", 0, []string{}},
+		{strings.Join(large, "\n") + "\n", 3, large[len(large)-3:]},
+		{strings.Join(large, "\r\n"), 1000, large[len(large)-1000:]},
+	}
+
+	mem := afero.NewMemMapFs()
+	for _, fs := range []afero.Fs{mem, noSeekFs{mem}} {
+		for i, d := range data {
+			afero.WriteFile(mem, "file.log", []byte(d.input), 0644)
+			res, err := TailLines(fs, "file.log", d.n)
+			if err != nil {
+				t.Errorf("Test %d failed. Unexpected error %s", i, err)
+			}
+			if len(res) != len(d.expected) {
+				t.Errorf("Test %d failed. Expected %d lines got %d", i, len(d.expected), len(res))
+				continue
+			}
+			for j, expected := range d.expected {
+				if expected != res[j] {
+					t.Errorf("Test %d failed. Expected %s got %s", i, expected, res[j])
+				}
+			}
+		}
+	}
+}
+
+func TestPrependFile(t *testing.T) {
+	type test struct {
+		original string
+		exists   bool
+		prepend  string
+		expected string
+	}
+	data := []test{
+		{"body\n", true, "// header\n", "// header\nbody\n"},
+		{"", true, "header", "header"},
+		{"body", true, "", "body"},
+		{"", false, "new", "new"},
+	}
+
+	for i, d := range data {
+		fs := afero.NewMemMapFs()
+		if d.exists {
+			afero.WriteFile(fs, "/dir/file.txt", []byte(d.original), 0600)
+		}
+		fs.MkdirAll("/dir", 0755)
+		if err := PrependFile(fs, "/dir/file.txt", []byte(d.prepend)); err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		res, _ := afero.ReadFile(fs, "/dir/file.txt")
+		if d.expected != string(res) {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+		if files, _ := afero.ReadDir(fs, "/dir"); len(files) != 1 {
+			t.Errorf("Test %d failed. Expected temporary file to be removed", i)
+		}
+		if info, _ := fs.Stat("/dir/file.txt"); d.exists && info.Mode().Perm() != 0600 {
+			t.Errorf("Test %d failed. Expected mode to be kept got %s", i, info.Mode())
+		}
+	}
+}
+
+func TestReadFileLimit(t *testing.T) {
+	type test struct {
+		input string
+		max   int64
+		err   error
+	}
+	data := []test{
+		{"hello", 10, nil},
+		{"hello", 5, nil},
+		{"hello", 4, ErrFileTooLarge},
+		{"", 0, nil},
+		{"a", 0, ErrFileTooLarge},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "file.txt", []byte(d.input), 0644)
+		res, err := ReadFileLimit(fs, "file.txt", d.max)
+		if d.err != err {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.err, err)
+		}
+		if d.err == nil && d.input != string(res) {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.input, res)
+		}
+	}
+}
+
+func TestAppendCapped(t *testing.T) {
+	type test struct {
+		original string
+		exists   bool
+		append   string
+		expected string
+	}
+	data := []test{
+		{"0123", true, "45", "012345"},
+		{"01234", true, "56789", "0123456789"},
+		{"0123456789", true, "ab", "23456789ab"},
+		{"0123", true, "abcdefghijkl", "cdefghijkl"},
+		{"", false, "new", "new"},
+		{"", false, "0123456789abc", "3456789abc"},
+		{"0123456789", true, "", "0123456789"},
+	}
+
+	for i, d := range data {
+		fs := afero.NewMemMapFs()
+		fs.MkdirAll("/logs", 0755)
+		if d.exists {
+			afero.WriteFile(fs, "/logs/app.log", []byte(d.original), 0600)
+		}
+		if err := AppendCapped(fs, "/logs/app.log", []byte(d.append), 10); err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		res, _ := afero.ReadFile(fs, "/logs/app.log")
+		if d.expected != string(res) {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+		if files, _ := afero.ReadDir(fs, "/logs"); len(files) != 1 {
+			t.Errorf("Test %d failed. Expected temporary file to be removed", i)
+		}
+	}
+
+	fs := afero.NewMemMapFs()
+	if err := AppendCapped(fs, "app.log", []byte("data"), 0); err == nil {
+		t.Error("Expected error for a zero max size")
+	}
+}
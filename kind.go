@@ -0,0 +1,58 @@
+package goutils
+
+import (
+	"os"
+
+	"github.com/spf13/afero"
+)
+
+// PathKind is the kind of entry found at a path by Classify
+type PathKind int
+
+// Kinds returned by Classify
+const (
+	KindFile PathKind = iota
+	KindDir
+	KindSymlink
+	KindMissing
+	KindOther
+)
+
+// String returns the name of the kind
+func (k PathKind) String() string {
+	switch k {
+	case KindFile:
+		return "file"
+	case KindDir:
+		return "dir"
+	case KindSymlink:
+		return "symlink"
+	case KindMissing:
+		return "missing"
+	}
+	return "other"
+}
+
+// Classify reports whether path is a regular file, a directory, a symlink,
+// which is not followed, or something else such as a device or socket.
+// A path that does not exist is KindMissing rather than an error.
+// Symlinks are reported on any filesystem implementing afero.Lstater.
+func Classify(fs afero.Fs, path string) (PathKind, error) {
+	info, err := lstatIfPossible(fs, path)
+	if os.IsNotExist(err) {
+		return KindMissing, nil
+	}
+	if err != nil {
+		return KindOther, err
+	}
+	mode := info.Mode()
+	switch {
+	case mode&os.ModeSymlink != 0:
+		return KindSymlink, nil
+	case info.IsDir():
+		return KindDir, nil
+	case mode.IsRegular():
+		return KindFile, nil
+	}
+	return KindOther, nil
+}
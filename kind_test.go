@@ -0,0 +1,81 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestClassify(t *testing.T) {
+	fs := newTestTree("/data", []string{"file.txt", "sub/nested.txt"})
+	fs.MkdirAll(filepath.FromSlash("/data/empty"), 0755)
+
+	type test struct {
+		input    string
+		expected PathKind
+	}
+	data := []test{
+		{"/data/file.txt", KindFile},
+		{"/data/sub/nested.txt", KindFile},
+		{"/data", KindDir},
+		// implicitly created directories are still directories
+		{"/data/sub", KindDir},
+		{"/data/empty", KindDir},
+		{"/data/missing.txt", KindMissing},
+		{"/data/missing/file.txt", KindMissing},
+	}
+	for i, d := range data {
+		res, err := Classify(fs, filepath.FromSlash(d.input))
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestClassifyOsFs(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+
+	afero.WriteFile(fs, filepath.Join(root, "file.txt"), []byte("data"), 0644)
+	fs.MkdirAll(filepath.Join(root, "dir"), 0755)
+	os.Symlink("file.txt", filepath.Join(root, "link"))
+	os.Symlink("dir", filepath.Join(root, "dirlink"))
+	os.Symlink("missing", filepath.Join(root, "dangling"))
+
+	type test struct {
+		input    string
+		expected PathKind
+	}
+	data := []test{
+		{"file.txt", KindFile},
+		{"dir", KindDir},
+		{"link", KindSymlink},
+		{"dirlink", KindSymlink},
+		{"dangling", KindSymlink},
+		{"missing", KindMissing},
+	}
+	if _, err := os.Stat(os.DevNull); err == nil && os.DevNull == "/dev/null" {
+		data = append(data, test{os.DevNull, KindOther})
+	}
+	// wrapping the OsFs must not hide the links
+	for _, wfs := range []afero.Fs{fs, afero.NewReadOnlyFs(fs)} {
+		for i, d := range data {
+			path := d.input
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(root, path)
+			}
+			res, err := Classify(wfs, path)
+			if err != nil {
+				t.Errorf("Test %d failed. Unexpected error %s", i, err)
+			}
+			if d.expected != res {
+				t.Errorf("Test %d failed. Expected %s got %s on %T", i, d.expected, res, wfs)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package goutils
+
+import (
+	"os"
+	"unicode/utf8"
+
+	"github.com/spf13/afero"
+)
+
+// MaxPathWindows is the classic windows MAX_PATH limit on path length
+const MaxPathWindows = 260
+
+// LongestPath walks root and returns the path, joined to root as in
+// afero.Walk, with the most characters along with its length. On a tie the
+// first path in walk order wins. Pass an absolute root to measure full paths.
+func LongestPath(fs afero.Fs, root string) (string, int, error) {
+	var longest string
+	max := -1
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if n := utf8.RuneCountInString(path); n > max {
+			longest, max = path, n
+		}
+		return nil
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return longest, max, nil
+}
+
+// PathsExceedingLength walks root and returns every path, joined to root as
+// in afero.Walk, longer than limit characters, such as MaxPathWindows.
+func PathsExceedingLength(fs afero.Fs, root string, limit int) ([]string, error) {
+	paths := []string{}
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(path) > limit {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
@@ -0,0 +1,64 @@
+package goutils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLongestPath(t *testing.T) {
+	type test struct {
+		files    []string
+		expected string
+	}
+	data := []test{
+		{[]string{"a.txt", "sub/b.txt", "sub/deep/c.txt"}, "/root/sub/deep/c.txt"},
+		{[]string{"a-very-long-file-name.txt", "sub/deep/c.txt"}, "/root/a-very-long-file-name.txt"},
+		// characters rather than bytes are counted
+		{[]string{"ééééé.txt", "abcdefg.txt"}, "/root/abcdefg.txt"},
+		// the first in walk order wins a tie
+		{[]string{"b.txt", "a.txt"}, "/root/a.txt"},
+		{nil, "/root"},
+	}
+
+	for i, d := range data {
+		fs := newTestTree("/root", d.files)
+		fs.MkdirAll(filepath.FromSlash("/root"), 0755)
+		path, n, err := LongestPath(fs, filepath.FromSlash("/root"))
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		expected := filepath.FromSlash(d.expected)
+		if expected != path {
+			t.Errorf("Test %d failed. Expected %s got %s", i, expected, path)
+		}
+		if len([]rune(expected)) != n {
+			t.Errorf("Test %d failed. Expected length %d got %d", i, len([]rune(expected)), n)
+		}
+	}
+}
+
+func TestPathsExceedingLength(t *testing.T) {
+	fs := newTestTree("/root", []string{"short.txt", "a-rather-long-name.txt", "sub/nested/file.txt"})
+	expected := []string{
+		"/root/a-rather-long-name.txt",
+		"/root/sub/nested",
+		"/root/sub/nested/file.txt",
+	}
+
+	res, err := PathsExceedingLength(fs, filepath.FromSlash("/root"), len("/root/sub/nested")-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %v got %v", expected, res)
+	}
+	for i, e := range expected {
+		if filepath.FromSlash(e) != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, res[i])
+		}
+	}
+
+	if res, _ := PathsExceedingLength(fs, filepath.FromSlash("/root"), MaxPathWindows); len(res) != 0 {
+		t.Errorf("Expected no paths over %d got %v", MaxPathWindows, res)
+	}
+}
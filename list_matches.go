@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	"regexp"
+
+	"github.com/spf13/afero"
 )
 
 // ListMatchesByRegex returns all matches by a regex
@@ -41,3 +43,21 @@ func ListNonMatchesByRegex(names []string, regex string) (nonmatches []string, e
 	}
 	return
 }
+
+// FindFilesByRegexp walks root and returns the relative paths of files matching re.
+// The regex is matched against the full slash separated relative path, so
+// both directories and extensions can be matched.
+//  FindFilesByRegexp(fs, "models", regexp.MustCompile(`^run\d+/.*\.mod$`))
+func FindFilesByRegexp(fs afero.Fs, root string, re *regexp.Regexp) ([]string, error) {
+	paths, err := TreeRelativePaths(fs, root)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, p := range paths {
+		if re.MatchString(p) {
+			matches = append(matches, p)
+		}
+	}
+	return matches, nil
+}
@@ -1,6 +1,9 @@
 package goutils
 
-import "testing"
+import (
+	"regexp"
+	"testing"
+)
 
 func TestListMatchesByRegex(t *testing.T) {
 	type input struct {
@@ -60,3 +63,39 @@ func TestListMatchesByRegex(t *testing.T) {
 		}
 	}
 }
+
+func TestFindFilesByRegexp(t *testing.T) {
+	fs := newTestTree("/models", []string{
+		"run001/run001.mod",
+		"run001/run001.lst",
+		"run002/run002.mod",
+		"scratch/run003.mod",
+		"notes.txt",
+	})
+	type test struct {
+		regex    string
+		expected []string
+	}
+	data := []test{
+		{`^run\d+/`, []string{"run001/run001.lst", "run001/run001.mod", "run002/run002.mod"}},
+		{`\.mod$`, []string{"run001/run001.mod", "run002/run002.mod", "scratch/run003.mod"}},
+		{`^run\d+/.*\.mod$`, []string{"run001/run001.mod", "run002/run002.mod"}},
+		{`\.csv$`, nil},
+	}
+
+	for i, d := range data {
+		res, err := FindFilesByRegexp(fs, "/models", regexp.MustCompile(d.regex))
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if len(res) != len(d.expected) {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+			continue
+		}
+		for j, expected := range d.expected {
+			if expected != res[j] {
+				t.Errorf("Test %d failed. Expected %s got %s", i, expected, res[j])
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package goutils
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/afero"
+)
+
+// windowsExecExts are the extensions treated as executable on windows,
+// where the execute bits of the file mode are meaningless
+var windowsExecExts = map[string]bool{
+	".exe": true,
+	".bat": true,
+	".cmd": true,
+	".com": true,
+}
+
+// IsExecutable checks if any execute bit is set on a regular file
+func IsExecutable(info os.FileInfo) bool {
+	return info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// IsExecutablePath checks if the file at path is executable.
+// On windows the file extension is checked instead of the file mode.
+func IsExecutablePath(fs afero.Fs, path string) (bool, error) {
+	info, err := fs.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if runtime.GOOS == "windows" {
+		return info.Mode().IsRegular() && windowsExecExts[strings.ToLower(filepath.Ext(path))], nil
+	}
+	return IsExecutable(info), nil
+}
+
+// EffectiveMode returns the mode a file created with requested would get
+// under umask. Only the permission bits of umask are applied.
+//  EffectiveMode(0666, 022) --> 0644
+func EffectiveMode(requested, umask os.FileMode) os.FileMode {
+	return requested &^ (umask & os.ModePerm)
+}
+
+// ErrChmodUnsupported is returned by ChmodTree on filesystems, such as
+// afero.ReadOnlyFs, that cannot change file modes
+var ErrChmodUnsupported = errors.New("changing file modes is not supported by the filesystem")
+
+// ChmodTree walks root and sets fileMode on regular files and dirMode on
+// directories, including root. Symlinks are skipped rather than followed.
+// Directories are changed after their contents, so a dirMode without
+// execute bits does not stop the walk. On filesystems that cannot change
+// modes the error wraps ErrChmodUnsupported.
+func ChmodTree(fs afero.Fs, root string, fileMode, dirMode os.FileMode) error {
+	var dirs []string
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.IsDir():
+			dirs = append(dirs, path)
+		case info.Mode().IsRegular():
+			return chmodOrUnsupported(fs, path, fileMode)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := chmodOrUnsupported(fs, dirs[i], dirMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chmodOrUnsupported changes the mode of path, reporting a filesystem
+// that rejects the operation itself as ErrChmodUnsupported
+func chmodOrUnsupported(fs afero.Fs, path string, mode os.FileMode) error {
+	err := fs.Chmod(path, mode)
+	if err == nil {
+		return nil
+	}
+	_, readOnly := fs.(*afero.ReadOnlyFs)
+	errno := err
+	if pe, ok := err.(*os.PathError); ok {
+		errno = pe.Err
+	}
+	if readOnly || errno == syscall.ENOTSUP || errno == syscall.EOPNOTSUPP {
+		return &os.PathError{Op: "chmod", Path: path, Err: ErrChmodUnsupported}
+	}
+	return err
+}
+
+// maxShebangLen bounds how much of a file Interpreter reads looking for
+// the end of the first line
+const maxShebangLen = 512
+
+// Interpreter returns the interpreter named by the #! line of a script,
+// without its arguments, or an empty string if the file has no such line.
+// For the "#!/usr/bin/env python" form the program run by env is returned.
+// Only the first line is read.
+//  #!/bin/bash -e --> /bin/bash
+//  #!/usr/bin/env python3 --> python3
+func Interpreter(fs afero.Fs, path string) (string, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	head := make([]byte, maxShebangLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	line := string(head[:n])
+	if !strings.HasPrefix(line, "#!") {
+		return "", nil
+	}
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(line[2:])
+	if len(fields) == 0 {
+		return "", nil
+	}
+	if filepath.Base(fields[0]) != "env" {
+		return fields[0], nil
+	}
+	// skip options to env such as -S and variable assignments
+	for _, field := range fields[1:] {
+		if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+			return field, nil
+		}
+	}
+	return fields[0], nil
+}
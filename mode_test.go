@@ -0,0 +1,204 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestIsExecutable(t *testing.T) {
+	type test struct {
+		input    os.FileMode
+		expected bool
+	}
+	data := []test{
+		{0755, true},
+		{0700, true},
+		{0744, true},
+		{0710, true},
+		{0701, true},
+		{0644, false},
+		{0600, false},
+		{0, false},
+		{os.ModeDir | 0755, false},
+		{os.ModeSymlink | 0777, false},
+	}
+
+	for i, d := range data {
+		res := IsExecutable(fakeFileInfo{name: "script", mode: d.input})
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+		}
+	}
+}
+
+func TestIsExecutablePath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("windows checks extensions rather than file modes")
+	}
+	type test struct {
+		input    os.FileMode
+		expected bool
+	}
+	data := []test{
+		{0755, true},
+		{0644, false},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "script.sh", []byte("#!/bin/sh"), d.input)
+		fs.Chmod("script.sh", d.input)
+		res, err := IsExecutablePath(fs, "script.sh")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+		}
+	}
+
+	if _, err := IsExecutablePath(fs, "missing.sh"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestEffectiveMode(t *testing.T) {
+	type test struct {
+		requested os.FileMode
+		umask     os.FileMode
+		expected  os.FileMode
+	}
+	data := []test{
+		{0666, 022, 0644},
+		{0777, 022, 0755},
+		{0666, 077, 0600},
+		{0777, 077, 0700},
+		{0644, 0, 0644},
+		{0600, 022, 0600},
+		{os.ModeDir | 0777, 027, os.ModeDir | 0750},
+		{os.ModeDir | 0777, os.ModeDir | 022, os.ModeDir | 0755},
+	}
+
+	for i, d := range data {
+		res := EffectiveMode(d.requested, d.umask)
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestChmodTree(t *testing.T) {
+	fs := newTestTree("/extract", []string{"a.txt", "bin/run.sh", "sub/deep/b.txt"})
+	fs.Chmod("/extract/bin/run.sh", 0777)
+	if err := ChmodTree(fs, "/extract", 0640, 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	type test struct {
+		input    string
+		expected os.FileMode
+	}
+	data := []test{
+		{"/extract", 0750},
+		{"/extract/a.txt", 0640},
+		{"/extract/bin", 0750},
+		{"/extract/bin/run.sh", 0640},
+		{"/extract/sub/deep", 0750},
+		{"/extract/sub/deep/b.txt", 0640},
+	}
+	for i, d := range data {
+		info, err := fs.Stat(filepath.FromSlash(d.input))
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+			continue
+		}
+		if d.expected != info.Mode().Perm() {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, info.Mode().Perm())
+		}
+	}
+
+	err := ChmodTree(afero.NewReadOnlyFs(fs), "/extract", 0644, 0755)
+	if pe, ok := err.(*os.PathError); !ok || pe.Err != ErrChmodUnsupported {
+		t.Errorf("Expected ErrChmodUnsupported for a read only filesystem got %v", err)
+	}
+	if err := ChmodTree(fs, "/missing", 0644, 0755); err == nil {
+		t.Error("Expected error for missing root")
+	}
+}
+
+func TestChmodTreeSkipsSymlinks(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+	outside, err := afero.TempDir(fs, "", "goutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outside)
+
+	target := filepath.Join(outside, "target.txt")
+	afero.WriteFile(fs, target, []byte("data"), 0600)
+	os.Symlink(target, filepath.Join(root, "link"))
+	os.Symlink(outside, filepath.Join(root, "dirlink"))
+	fs.Chmod(outside, 0700)
+
+	if err := ChmodTree(fs, root, 0644, 0755); err != nil {
+		t.Fatal(err)
+	}
+	type test struct {
+		input    string
+		expected os.FileMode
+	}
+	data := []test{
+		{target, 0600},
+		{outside, 0700},
+		{root, 0755},
+	}
+	for i, d := range data {
+		info, _ := fs.Stat(d.input)
+		if d.expected != info.Mode().Perm() {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, info.Mode().Perm())
+		}
+	}
+}
+
+func TestInterpreter(t *testing.T) {
+	type test struct {
+		input    string
+		expected string
+	}
+	data := []test{
+		{"#!/bin/bash\necho hi\n", "/bin/bash"},
+		{"#!/bin/bash -e\r\necho hi\r\n", "/bin/bash"},
+		{"#! /bin/sh\n", "/bin/sh"},
+		{"#!/usr/bin/env python\nprint('hi')\n", "python"},
+		{"#!/usr/bin/env -S python3 -u\n", "python3"},
+		{"#!/usr/bin/env LANG=C perl\n", "perl"},
+		{"#!/usr/bin/env\n", "/usr/bin/env"},
+		{"#!/usr/bin/Rscript", "/usr/bin/Rscript"},
+		{"#!\n", ""},
+		{"plain text\n#!/bin/bash\n", ""},
+		{" #!/bin/bash\n", ""},
+		{"", ""},
+		{"#!/bin/sh\n" + strings.Repeat("x", 2*maxShebangLen), "/bin/sh"},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "script", []byte(d.input), 0755)
+		res, err := Interpreter(fs, "script")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+	if _, err := Interpreter(fs, "missing"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
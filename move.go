@@ -0,0 +1,84 @@
+package goutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/afero"
+)
+
+// MoveFile moves the file src to dst, creating any missing directories for
+// dst. A rename is tried first, and if it fails because src and dst are on
+// different devices the file is copied and src is removed instead.
+func MoveFile(fs afero.Fs, src, dst string) error {
+	info, err := fs.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	err = fs.Rename(src, dst)
+	if err == nil || !isCrossDevice(err) {
+		return err
+	}
+	if _, err := CopyFS(fs, src, dst); err != nil {
+		return err
+	}
+	if err := fs.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return fs.Remove(src)
+}
+
+// isCrossDevice checks if err is from renaming across devices
+func isCrossDevice(err error) bool {
+	if le, ok := err.(*os.LinkError); ok {
+		err = le.Err
+	}
+	return err == syscall.EXDEV
+}
+
+// RenameAll renames each file in dir, skipping dotfiles as ListFiles does,
+// to the name given by transform and returns a map of the old to the new
+// names of the files that changed. All new names are checked before anything
+// is renamed, so a transform that maps two files to one name, or onto any
+// other existing entry of dir, results in an error with nothing changed.
+func RenameAll(fs afero.Fs, dir string, transform func(name string) string) (map[string]string, error) {
+	fd, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, info := range fd {
+		existing[info.Name()] = true
+	}
+	renames := make(map[string]string)
+	claimed := make(map[string]string)
+	for _, name := range ListFiles(fd) {
+		newName := transform(name)
+		if newName == name {
+			continue
+		}
+		if newName == "" || strings.ContainsAny(newName, `/\`) || newName == "." || newName == ".." {
+			return nil, fmt.Errorf("invalid name %q for %s", newName, name)
+		}
+		if other, ok := claimed[newName]; ok {
+			return nil, fmt.Errorf("%s and %s would both be renamed to %s", other, name, newName)
+		}
+		if existing[newName] {
+			return nil, fmt.Errorf("cannot rename %s, %s already exists", name, newName)
+		}
+		claimed[newName] = name
+		renames[name] = newName
+	}
+	for name, newName := range renames {
+		if err := fs.Rename(filepath.Join(dir, name), filepath.Join(dir, newName)); err != nil {
+			return nil, err
+		}
+	}
+	return renames, nil
+}
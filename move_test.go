@@ -0,0 +1,131 @@
+package goutils
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// crossDeviceFs wraps a filesystem so every rename fails as if across devices
+type crossDeviceFs struct {
+	afero.Fs
+}
+
+func (fs crossDeviceFs) Rename(oldname, newname string) error {
+	return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: syscall.EXDEV}
+}
+
+// failingRenameFs wraps a filesystem so every rename fails for another reason
+type failingRenameFs struct {
+	afero.Fs
+}
+
+func (fs failingRenameFs) Rename(oldname, newname string) error {
+	return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: syscall.EACCES}
+}
+
+func TestMoveFile(t *testing.T) {
+	type test struct {
+		name string
+		wrap func(afero.Fs) afero.Fs
+	}
+	data := []test{
+		{"rename", func(fs afero.Fs) afero.Fs { return fs }},
+		{"cross device", func(fs afero.Fs) afero.Fs { return crossDeviceFs{fs} }},
+	}
+
+	for i, d := range data {
+		mem := afero.NewMemMapFs()
+		afero.WriteFile(mem, "/src/file.txt", []byte("content"), 0600)
+		fs := d.wrap(mem)
+		if err := MoveFile(fs, "/src/file.txt", "/dst/nested/file.txt"); err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+			continue
+		}
+		if exists, _ := Exists(mem, "/src/file.txt"); exists {
+			t.Errorf("Test %d failed. Expected source to be removed by %s", i, d.name)
+		}
+		content, _ := afero.ReadFile(mem, "/dst/nested/file.txt")
+		if string(content) != "content" {
+			t.Errorf("Test %d failed. Expected content got %s", i, content)
+		}
+		if info, _ := mem.Stat("/dst/nested/file.txt"); info.Mode().Perm() != 0600 {
+			t.Errorf("Test %d failed. Expected mode to be kept got %s", i, info.Mode())
+		}
+	}
+
+	mem := afero.NewMemMapFs()
+	afero.WriteFile(mem, "/src/file.txt", []byte("content"), 0644)
+	if err := MoveFile(failingRenameFs{mem}, "/src/file.txt", "/dst/file.txt"); err == nil {
+		t.Error("Expected other rename errors to be returned")
+	}
+	if exists, _ := Exists(mem, "/src/file.txt"); !exists {
+		t.Error("Expected source to remain after a failed rename")
+	}
+	if err := MoveFile(mem, "/src/missing.txt", "/dst/file.txt"); err == nil {
+		t.Error("Expected error for missing source")
+	}
+}
+
+func TestRenameAll(t *testing.T) {
+	type test struct {
+		files     []string
+		transform func(string) string
+		expected  map[string]string
+		tree      []string
+		err       bool
+	}
+	data := []test{
+		{
+			[]string{"Run001.MOD", "run002.mod", "Notes.TXT", ".Hidden", "Sub/File.txt"},
+			strings.ToLower,
+			map[string]string{"Run001.MOD": "run001.mod", "Notes.TXT": "notes.txt"},
+			[]string{".Hidden", "Sub/File.txt", "notes.txt", "run001.mod", "run002.mod"},
+			false,
+		},
+		{
+			[]string{"a-1.txt", "a_1.txt", "b.txt"},
+			func(name string) string { return strings.Replace(name, "_", "-", -1) },
+			nil,
+			[]string{"a-1.txt", "a_1.txt", "b.txt"},
+			true,
+		},
+		{
+			[]string{"A.txt", "B.txt", "a.TXT"},
+			strings.ToLower,
+			nil,
+			[]string{"A.txt", "B.txt", "a.TXT"},
+			true,
+		},
+		{
+			[]string{"a.txt", "b.txt"},
+			func(name string) string { return "sub/" + name },
+			nil,
+			[]string{"a.txt", "b.txt"},
+			true,
+		},
+	}
+
+	for i, d := range data {
+		fs := newTestTree("/dir", d.files)
+		res, err := RenameAll(fs, "/dir", d.transform)
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if len(res) != len(d.expected) {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+		}
+		for old, e := range d.expected {
+			if e != res[old] {
+				t.Errorf("Test %d failed. Expected %s to become %s got %s", i, old, e, res[old])
+			}
+		}
+		tree, _ := TreeRelativePaths(fs, "/dir")
+		if strings.Join(d.tree, ",") != strings.Join(tree, ",") {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.tree, tree)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package goutils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/afero"
+)
+
+// DetectLineEnding returns the line ending used by the first line of the
+// file, either "\r\n" or "\n". An empty string is returned if the file
+// contains no newline.
+func DetectLineEnding(fs afero.Fs, path string) (string, error) {
+	inFile, err := fs.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer inFile.Close()
+	r := bufio.NewReader(inFile)
+	var prev byte
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if b == '\n' {
+			if prev == '\r' {
+				return "\r\n", nil
+			}
+			return "\n", nil
+		}
+		prev = b
+	}
+}
+
+// NormalizeLineEndings rewrites the file at path so every line ends with
+// style, which must be "\n" or "\r\n". The file is written atomically and
+// is left untouched if it already consistently uses style.
+func NormalizeLineEndings(fs afero.Fs, path string, style string) error {
+	if style != "\n" && style != "\r\n" {
+		return fmt.Errorf("unsupported line ending %q", style)
+	}
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return err
+	}
+	normalized := bytes.Replace(content, []byte("\r\n"), []byte("\n"), -1)
+	if style == "\r\n" {
+		normalized = bytes.Replace(normalized, []byte("\n"), []byte("\r\n"), -1)
+	}
+	if bytes.Equal(content, normalized) {
+		return nil
+	}
+	return writeAtomic(fs, path, func(w io.Writer) error {
+		_, err := w.Write(normalized)
+		return err
+	})
+}
+
+// HasTrailingNewline checks if the file at path ends with a newline, only
+// reading its final byte. An empty file has no trailing newline.
+func HasTrailingNewline(fs afero.Fs, path string) (bool, error) {
+	inFile, err := fs.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer inFile.Close()
+	info, err := inFile.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.Size() == 0 {
+		return false, nil
+	}
+	last := make([]byte, 1)
+	if _, err := inFile.ReadAt(last, info.Size()-1); err != nil && err != io.EOF {
+		return false, err
+	}
+	return last[0] == '\n', nil
+}
+
+// EnsureTrailingNewline appends a newline to the file at path if it does not
+// end with one, matching the line ending already used by the file, and
+// reports whether the file was modified. Empty files are left empty.
+func EnsureTrailingNewline(fs afero.Fs, path string) (bool, error) {
+	info, err := fs.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if info.Size() == 0 {
+		return false, nil
+	}
+	hasNewline, err := HasTrailingNewline(fs, path)
+	if err != nil || hasNewline {
+		return false, err
+	}
+	style, err := DetectLineEnding(fs, path)
+	if err != nil {
+		return false, err
+	}
+	if style == "" {
+		style = "\n"
+	}
+	f, err := fs.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.Write([]byte(style)); err != nil {
+		f.Close()
+		return false, err
+	}
+	return true, f.Close()
+}
@@ -0,0 +1,133 @@
+package goutils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func TestDetectLineEnding(t *testing.T) {
+	type test struct {
+		input    string
+		expected string
+	}
+	data := []test{
+		{"one\ntwo\n", "\n"},
+		{"one\r\ntwo\r\n", "\r\n"},
+		{"one\r\ntwo\n", "\r\n"},
+		{"one\ntwo\r\n", "\n"},
+		{"\r\n", "\r\n"},
+		{"one\rtwo", ""},
+		{"no newline", ""},
+		{"", ""},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "file.txt", []byte(d.input), 0644)
+		res, err := DetectLineEnding(fs, "file.txt")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected, res)
+		}
+	}
+
+	if _, err := DetectLineEnding(fs, "missing.txt"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	type test struct {
+		input    string
+		style    string
+		expected string
+	}
+	data := []test{
+		{"one\r\ntwo\r\n", "\n", "one\ntwo\n"},
+		{"one\ntwo\n", "\r\n", "one\r\ntwo\r\n"},
+		{"one\r\ntwo\nthree", "\n", "one\ntwo\nthree"},
+		{"one\r\ntwo\nthree", "\r\n", "one\r\ntwo\r\nthree"},
+		{"one\ntwo", "\n", "one\ntwo"},
+		{"", "\r\n", ""},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "file.txt", []byte(d.input), 0644)
+		if err := NormalizeLineEndings(fs, "file.txt", d.style); err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		res, _ := afero.ReadFile(fs, "file.txt")
+		if d.expected != string(res) {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected, res)
+		}
+	}
+
+	if err := NormalizeLineEndings(fs, "file.txt", "\r"); err == nil {
+		t.Error("Expected error for unsupported line ending")
+	}
+}
+
+func TestNormalizeLineEndingsNoop(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "file.txt", []byte("one\ntwo\n"), 0644)
+	past := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	fs.Chtimes("file.txt", past, past)
+	if err := NormalizeLineEndings(fs, "file.txt", "\n"); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := fs.Stat("file.txt"); !info.ModTime().Equal(past) {
+		t.Error("Expected consistent file not to be rewritten")
+	}
+}
+
+func TestTrailingNewline(t *testing.T) {
+	type test struct {
+		input    string
+		has      bool
+		modified bool
+		expected string
+	}
+	data := []test{
+		{"one\ntwo\n", true, false, "one\ntwo\n"},
+		{"one\ntwo", false, true, "one\ntwo\n"},
+		{"one\r\ntwo", false, true, "one\r\ntwo\r\n"},
+		{"single line", false, true, "single line\n"},
+		{"\n", true, false, "\n"},
+		{"", false, false, ""},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "file.txt", []byte(d.input), 0644)
+		has, err := HasTrailingNewline(fs, "file.txt")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.has != has {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.has, has)
+		}
+		modified, err := EnsureTrailingNewline(fs, "file.txt")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.modified != modified {
+			t.Errorf("Test %d failed. Expected modified %v got %v", i, d.modified, modified)
+		}
+		res, _ := afero.ReadFile(fs, "file.txt")
+		if d.expected != string(res) {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected, res)
+		}
+	}
+
+	if _, err := HasTrailingNewline(fs, "missing.txt"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+	if _, err := EnsureTrailingNewline(fs, "missing.txt"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -0,0 +1,71 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// StripNumericPrefix splits a leading ordering number and its separator,
+// one of "-", "_" or ".", from name. Names without such a prefix return an
+// empty prefix and the full name, as do names where the digits make up the
+// whole file name before the extension.
+//  01-intro.md --> 01-, intro.md
+//  2.md --> "", 2.md
+func StripNumericPrefix(name string) (prefix string, rest string) {
+	i := 0
+	for i < len(name) && name[i] >= '0' && name[i] <= '9' {
+		i++
+	}
+	if i == 0 || i+1 >= len(name) {
+		return "", name
+	}
+	switch name[i] {
+	case '-', '_':
+	case '.':
+		if filepath.Ext(name) == name[i:] {
+			return "", name
+		}
+	default:
+		return "", name
+	}
+	return name[:i+1], name[i+1:]
+}
+
+// OrderWeight parses the numeric prefix recognized by StripNumericPrefix,
+// so 10-x.md can be sorted after 2-x.md by number rather than lexically.
+// The bool reports whether name had a prefix.
+//  10-advanced.md --> 10, true
+func OrderWeight(name string) (int, bool) {
+	prefix, _ := StripNumericPrefix(name)
+	if prefix == "" {
+		return 0, false
+	}
+	weight, err := strconv.Atoi(prefix[:len(prefix)-1])
+	if err != nil {
+		return 0, false
+	}
+	return weight, true
+}
+
+// ListOrdered is the same as ListFiles but sorts the names by OrderWeight,
+// the way chapters are ordered by documentation generators. Names with the
+// same weight are sorted by name, and names without a numeric prefix come
+// last, also sorted by name.
+//  10-c.md, b.md, 2-b.md --> 2-b.md, 10-c.md, b.md
+func ListOrdered(fd []os.FileInfo) []string {
+	files := ListFiles(fd)
+	sort.Slice(files, func(i, j int) bool {
+		wi, oki := OrderWeight(files[i])
+		wj, okj := OrderWeight(files[j])
+		if oki != okj {
+			return oki
+		}
+		if wi != wj {
+			return wi < wj
+		}
+		return files[i] < files[j]
+	})
+	return files
+}
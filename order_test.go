@@ -0,0 +1,103 @@
+package goutils
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestStripNumericPrefix(t *testing.T) {
+	type split struct {
+		prefix string
+		rest   string
+	}
+	type test struct {
+		input    string
+		expected split
+	}
+	data := []test{
+		{"01-intro.md", split{"01-", "intro.md"}},
+		{"02_setup.md", split{"02_", "setup.md"}},
+		{"03.usage.md", split{"03.", "usage.md"}},
+		{"10-advanced-topics.md", split{"10-", "advanced-topics.md"}},
+		{"7-a", split{"7-", "a"}},
+		{"intro.md", split{"", "intro.md"}},
+		{"v2-notes.md", split{"", "v2-notes.md"}},
+		{"2019report.md", split{"", "2019report.md"}},
+		{"01 intro.md", split{"", "01 intro.md"}},
+		{"2.md", split{"", "2.md"}},
+		{"01-", split{"", "01-"}},
+		{"42", split{"", "42"}},
+		{"", split{"", ""}},
+	}
+
+	for i, d := range data {
+		prefix, rest := StripNumericPrefix(d.input)
+		if d.expected.prefix != prefix || d.expected.rest != rest {
+			t.Errorf("Test %d failed. Expected %v got %s, %s", i, d.expected, prefix, rest)
+		}
+	}
+}
+
+func TestOrderWeight(t *testing.T) {
+	type weight struct {
+		weight int
+		ok     bool
+	}
+	type test struct {
+		input    string
+		expected weight
+	}
+	data := []test{
+		{"01-intro.md", weight{1, true}},
+		{"2-b.md", weight{2, true}},
+		{"10-c.md", weight{10, true}},
+		{"007_bond.md", weight{7, true}},
+		{"0.zero.md", weight{0, true}},
+		{"intro.md", weight{0, false}},
+		{"2.md", weight{0, false}},
+		{"99999999999999999999-huge.md", weight{0, false}},
+	}
+	for i, d := range data {
+		w, ok := OrderWeight(d.input)
+		if d.expected.weight != w || d.expected.ok != ok {
+			t.Errorf("Test %d failed. Expected %v got %d, %v", i, d.expected, w, ok)
+		}
+	}
+
+	names := []string{"10-c.md", "2-b.md", "1-a.md"}
+	lexical := append([]string{}, names...)
+	sort.Strings(lexical)
+	if strings.Join(lexical, ",") != "1-a.md,10-c.md,2-b.md" {
+		t.Errorf("Expected lexical order to misplace 10-c.md got %v", lexical)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		wi, _ := OrderWeight(names[i])
+		wj, _ := OrderWeight(names[j])
+		return wi < wj
+	})
+	if strings.Join(names, ",") != "1-a.md,2-b.md,10-c.md" {
+		t.Errorf("Expected numeric order got %v", names)
+	}
+}
+
+func TestListOrdered(t *testing.T) {
+	fd := []os.FileInfo{
+		fakeFileInfo{name: "10-c.md"},
+		fakeFileInfo{name: "appendix.md"},
+		fakeFileInfo{name: "2-b.md"},
+		fakeFileInfo{name: ".1-hidden.md"},
+		fakeFileInfo{name: "01-a.md"},
+		fakeFileInfo{name: "02-a.md"},
+		fakeFileInfo{name: "3-chapter", mode: os.ModeDir},
+		fakeFileInfo{name: "README.md"},
+		fakeFileInfo{name: "1-z.md"},
+	}
+	expected := []string{"01-a.md", "1-z.md", "02-a.md", "2-b.md", "10-c.md", "README.md", "appendix.md"}
+
+	res := ListOrdered(fd)
+	if strings.Join(expected, ",") != strings.Join(res, ",") {
+		t.Errorf("Expected %v got %v", expected, res)
+	}
+}
@@ -0,0 +1,368 @@
+package goutils
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SplitAt splits a path around the first component equal to component,
+// returning the path before and after it. Matching is done per component
+// so "a/bc/d" is not split by "b".
+//  SplitAt("a/b/c/d", "c") --> "a/b", "d", true
+// If the component is not present, the full path is returned as before.
+func SplitAt(path, component string) (before, after string, found bool) {
+	parts := splitComponents(path)
+	for i, p := range parts {
+		if p != component {
+			continue
+		}
+		before = joinComponents(parts[:i])
+		if before == "" && i > 0 {
+			// the only preceding component was the root
+			before = FilePathSeparator
+		}
+		return before, joinComponents(parts[i+1:]), true
+	}
+	return path, "", false
+}
+
+// CommonPrefix returns the longest leading path shared by all paths,
+// compared component by component so "a/bc" and "a/bd" share "a"
+// rather than "a/b". An empty string is returned if nothing is shared.
+func CommonPrefix(paths []string) string {
+	common := commonComponents(paths)
+	prefix := joinComponents(common)
+	if prefix == "" && len(common) > 0 {
+		return FilePathSeparator
+	}
+	return prefix
+}
+
+// CommonBaseAndTails returns the common directory of two paths, as given by
+// CommonPrefix, along with the remaining portion of each path.
+//  CommonBaseAndTails("a/b/c", "a/b/d/e") --> "a/b", "c", "d/e"
+func CommonBaseAndTails(a, b string) (base, tailA, tailB string) {
+	n := len(commonComponents([]string{a, b}))
+	tailA = joinComponents(splitComponents(filepath.Clean(a))[n:])
+	tailB = joinComponents(splitComponents(filepath.Clean(b))[n:])
+	return CommonPrefix([]string{a, b}), tailA, tailB
+}
+
+// commonComponents returns the leading components shared by all cleaned paths
+func commonComponents(paths []string) []string {
+	if len(paths) == 0 {
+		return nil
+	}
+	common := splitComponents(filepath.Clean(paths[0]))
+	for _, p := range paths[1:] {
+		parts := splitComponents(filepath.Clean(p))
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	return common
+}
+
+// MapPaths rebases each path from srcRoot to dstRoot, rewriting extensions
+// found in extMap along the way. Extensions are given without the dot.
+//  MapPaths([]string{"content/a/b.md"}, "content", "public", map[string]string{"md": "html"})
+//  --> public/a/b.html
+// A path outside of srcRoot results in an error.
+func MapPaths(paths []string, srcRoot, dstRoot string, extMap map[string]string) ([]string, error) {
+	var mapped []string
+	for _, p := range paths {
+		if !isWithin(srcRoot, p) {
+			return nil, fmt.Errorf("%s is not within %s", p, srcRoot)
+		}
+		rel, err := GetRelativePath(p, srcRoot)
+		if err != nil {
+			return nil, err
+		}
+		_, ext := FileAndExt(rel)
+		if newExt, ok := extMap[strings.TrimPrefix(ext, ".")]; ok && ext != "" {
+			rel = strings.TrimSuffix(rel, ext) + "." + newExt
+		}
+		mapped = append(mapped, filepath.Join(dstRoot, rel))
+	}
+	return mapped, nil
+}
+
+// TrimPathPrefix removes prefix from the start of path, only matching whole
+// components, and reports whether it was removed. Unlike strings.TrimPrefix
+//  TrimPathPrefix("a/bc", "a/b") --> "a/bc", false
+// Both paths are cleaned before comparison, a path equal to the prefix
+// results in an empty remainder.
+func TrimPathPrefix(path, prefix string) (string, bool) {
+	p := filepath.Clean(path)
+	pre := filepath.Clean(prefix)
+	switch {
+	case p == pre:
+		return "", true
+	case pre == ".":
+		return p, !filepath.IsAbs(p)
+	case strings.HasSuffix(pre, FilePathSeparator):
+		// only a root, such as "/", keeps its trailing separator when cleaned
+		if strings.HasPrefix(p, pre) {
+			return p[len(pre):], true
+		}
+	case strings.HasPrefix(p, pre+FilePathSeparator):
+		return p[len(pre)+1:], true
+	}
+	return path, false
+}
+
+// ResolveAgainst resolves a relative path against the working directory cwd,
+// rather than the working directory of the process, and cleans the result.
+// An absolute path is only cleaned.
+//  ResolveAgainst("/home/user", "../data/./run001.mod") --> /home/data/run001.mod
+func ResolveAgainst(cwd, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(cwd, path)
+}
+
+// RelativeLink returns the slash separated href to navigate from the page
+// fromPage to the page toPage. Targets in the same directory or below are
+// given a leading "./".
+//  RelativeLink("docs/guide/intro.html", "docs/api/index.html", false) --> ../api/index.html
+// With trimIndex, a target named index.html is linked by its directory
+//  RelativeLink("docs/guide/intro.html", "docs/api/index.html", true) --> ../api/
+func RelativeLink(fromPage, toPage string, trimIndex bool) (string, error) {
+	fromDir := path.Dir(filepath.ToSlash(fromPage))
+	rel, err := GetRelativePath(filepath.FromSlash(toPage), filepath.FromSlash(fromDir))
+	if err != nil {
+		return "", err
+	}
+	link := filepath.ToSlash(rel)
+	if link == "." || link == "./" {
+		// the directory of fromPage itself
+		return "./", nil
+	}
+	if trimIndex && path.Base(link) == "index.html" {
+		link = strings.TrimSuffix(link, "index.html")
+	}
+	if link != ".." && !strings.HasPrefix(link, "../") {
+		link = "./" + link
+	}
+	return link, nil
+}
+
+// RelLexical returns the slash separated path of target relative to base.
+// It works purely on the strings given, without touching the filesystem or
+// keeping trailing separators as GetRelativePath does, so the result is
+// predictable when generating URLs.
+// An error is returned if only one of the paths is absolute, or if base
+// climbs above its start with ".." further than target does.
+func RelLexical(base, target string) (string, error) {
+	if filepath.IsAbs(base) != filepath.IsAbs(target) {
+		return "", fmt.Errorf("cannot make %s relative to %s", target, base)
+	}
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// RebaseAll finds the common directory of paths and returns it along with
+// each path relative to it.
+//  RebaseAll([]string{"/data/a/run001.mod", "/data/b/run002.mod"})
+//  --> /data, [a/run001.mod b/run002.mod]
+// The base is always a directory of the inputs, so a single path is
+// rebased against its parent directory.
+func RebaseAll(paths []string) (base string, rebased []string, err error) {
+	if len(paths) == 0 {
+		return "", nil, errors.New("no paths given")
+	}
+	base = CommonPrefix(paths)
+	for _, p := range paths {
+		if filepath.Clean(p) == base {
+			base = filepath.Dir(base)
+			break
+		}
+	}
+	if base == "" {
+		base = "."
+	}
+	for _, p := range paths {
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return "", nil, err
+		}
+		rebased = append(rebased, rel)
+	}
+	return base, rebased, nil
+}
+
+// SafeJoin joins an untrusted relative path, such as an archive entry name,
+// onto base and guarantees the result stays within base. Absolute paths and
+// any traversal out of base with ".." result in an error, preventing
+// "zip slip" style extraction outside of the destination.
+func SafeJoin(base, untrusted string) (string, error) {
+	if filepath.IsAbs(untrusted) || filepath.VolumeName(untrusted) != "" || strings.HasPrefix(filepath.ToSlash(untrusted), "/") {
+		return "", fmt.Errorf("%s is an absolute path", untrusted)
+	}
+	joined := filepath.Join(base, filepath.Clean(untrusted))
+	if !isWithin(base, joined) {
+		return "", fmt.Errorf("%s escapes %s", untrusted, base)
+	}
+	return joined, nil
+}
+
+// isWithin lexically checks whether path is root or is nested below root
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+FilePathSeparator)
+}
+
+// splitComponents splits a path into its components, an absolute path
+// will have an empty string as its first component
+func splitComponents(path string) []string {
+	return strings.Split(filepath.ToSlash(path), "/")
+}
+
+// joinComponents joins components back into a path with the os separator
+func joinComponents(parts []string) string {
+	return strings.Join(parts, FilePathSeparator)
+}
+
+// PathSortKey returns a key for ordering paths the same way on every
+// platform. Both / and \ are treated as separators and components are
+// compared one at a time, so a directory sorts directly before its
+// children and a parent before anything nested in it.
+//  sort.Slice(paths, func(i, j int) bool { return PathSortKey(paths[i]) < PathSortKey(paths[j]) })
+//  a/b, a/b/c, a/b-c
+func PathSortKey(p string) string {
+	p = path.Clean(strings.Replace(filepath.ToSlash(p), `\`, "/", -1))
+	// NUL sorts before any byte that can appear in a component
+	return strings.Replace(p, "/", "\x00", -1)
+}
+
+// TarName returns the archive member name of path when archiving root:
+// relative to root, slash separated and without a leading "./" or "/".
+// A directory, marked by a trailing separator on path, keeps a trailing slash.
+//  TarName("/src", "/src/docs/intro.md") --> docs/intro.md
+//  TarName("/src", "/src/docs/") --> docs/
+// An error is returned if path is root itself or is not within root.
+func TarName(root, path string) (string, error) {
+	isDir := strings.HasSuffix(filepath.ToSlash(path), "/")
+	rel, err := RelLexical(root, path)
+	if err != nil {
+		return "", err
+	}
+	if rel == "." {
+		return "", fmt.Errorf("%s is the archive root", path)
+	}
+	if !isWithin(root, path) {
+		return "", fmt.Errorf("%s is not within %s", path, root)
+	}
+	if isDir {
+		rel += "/"
+	}
+	return rel, nil
+}
+
+// MostCommonDir returns the deepest directory containing more than half of
+// the files at paths, a best guess at a project root when a few paths lie
+// elsewhere. Unlike CommonPrefix, outliers do not pull the result up to a
+// shared ancestor. An empty string is returned when no directory holds a
+// majority.
+//  [/proj/src/a.go /proj/src/pkg/b.go /proj/go.mod /tmp/x.go] --> /proj
+func MostCommonDir(paths []string) string {
+	counts := make(map[string]int)
+	for _, p := range paths {
+		dir := filepath.Dir(filepath.Clean(p))
+		for {
+			counts[dir]++
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+	// directories holding a majority all lie on one chain of ancestors,
+	// so the longest is the deepest
+	best := ""
+	for dir, n := range counts {
+		if 2*n > len(paths) && len(dir) > len(best) {
+			best = dir
+		}
+	}
+	return best
+}
+
+// CacheKey returns a canonical key for path so that every spelling of the
+// same logical file maps to one cache entry. The path is cleaned and slash
+// separated, and also lower cased when foldCase is set, as
+// IsCaseInsensitiveFS reports for the filesystem holding it.
+//  CacheKey("./Docs//Intro.md", true) --> docs/intro.md
+func CacheKey(p string, foldCase bool) string {
+	key := path.Clean(filepath.ToSlash(filepath.Clean(p)))
+	if foldCase {
+		key = strings.ToLower(key)
+	}
+	return key
+}
+
+// RelBetween returns the slash separated path leading from the directory of
+// a to b, for generating links where an error is inconvenient. When the two
+// cannot be related, such as an absolute and a relative path, b is returned
+// cleaned instead.
+//  RelBetween("docs/api/index.md", "docs/intro.md") --> ../intro.md
+func RelBetween(a, b string) string {
+	rel, err := RelLexical(filepath.Dir(a), b)
+	if err != nil {
+		return filepath.ToSlash(filepath.Clean(b))
+	}
+	return rel
+}
+
+// StripDotSegments removes "." segments and repeated separators from path
+// without resolving "..", which unlike filepath.Clean keeps the result
+// correct when a directory is a symlink. A leading separator is kept.
+//  ./foo/./bar --> foo/bar
+//  a/./../b --> a/../b
+func StripDotSegments(path string) string {
+	stripped := joinComponents(withoutDotSegments(splitComponents(path)))
+	if strings.HasPrefix(filepath.ToSlash(path), "/") {
+		return FilePathSeparator + stripped
+	}
+	if stripped == "" {
+		return "."
+	}
+	return stripped
+}
+
+// RelOrAbs returns the slash separated path of target relative to the
+// directory base, unless that needs more than maxUp leading ".." segments or
+// cannot be computed, in which case target is returned cleaned. This keeps
+// generated links free of long ../../../.. chains.
+//  RelOrAbs("/site/a/b", "/site/a/c.css", 2) --> ../c.css
+//  RelOrAbs("/site/a/b/c", "/site/x.css", 2) --> /site/x.css
+func RelOrAbs(base, target string, maxUp int) string {
+	rel, err := RelLexical(base, target)
+	if err != nil {
+		return filepath.ToSlash(filepath.Clean(target))
+	}
+	up := 0
+	for _, part := range strings.Split(rel, "/") {
+		if part != ".." {
+			break
+		}
+		up++
+	}
+	if up > maxUp {
+		return filepath.ToSlash(filepath.Clean(target))
+	}
+	return rel
+}
@@ -0,0 +1,565 @@
+package goutils
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestSplitAt(t *testing.T) {
+	type splitResult struct {
+		before string
+		after  string
+		found  bool
+	}
+	type test struct {
+		path      string
+		component string
+		expected  splitResult
+	}
+	data := []test{
+		{"a/b/c/d", "c", splitResult{"a/b", "d", true}},
+		{"c/d/e", "c", splitResult{"", "d/e", true}},
+		{"/c/d", "c", splitResult{"/", "d", true}},
+		{"a/b/c", "c", splitResult{"a/b", "", true}},
+		{"a/bc/d", "b", splitResult{"a/bc/d", "", false}},
+		{"a/b/d", "c", splitResult{"a/b/d", "", false}},
+	}
+
+	for i, d := range data {
+		before, after, found := SplitAt(filepath.FromSlash(d.path), d.component)
+		if before != filepath.FromSlash(d.expected.before) {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.before, before)
+		}
+		if after != filepath.FromSlash(d.expected.after) {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.after, after)
+		}
+		if found != d.expected.found {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected.found, found)
+		}
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	type test struct {
+		input    []string
+		expected string
+	}
+	data := []test{
+		{[]string{"a/b/c", "a/b/d/e"}, "a/b"},
+		{[]string{"a/bc", "a/bd"}, "a"},
+		{[]string{"/a/b", "/c"}, "/"},
+		{[]string{"a/b", "c/d"}, ""},
+		{[]string{"a/b/c"}, "a/b/c"},
+		{[]string{}, ""},
+	}
+
+	for i, d := range data {
+		var input []string
+		for _, p := range d.input {
+			input = append(input, filepath.FromSlash(p))
+		}
+		res := CommonPrefix(input)
+		if filepath.FromSlash(d.expected) != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestCommonBaseAndTails(t *testing.T) {
+	type baseAndTails struct {
+		base  string
+		tailA string
+		tailB string
+	}
+	type test struct {
+		a        string
+		b        string
+		expected baseAndTails
+	}
+	data := []test{
+		{"a/b/c", "a/b/d/e", baseAndTails{"a/b", "c", "d/e"}},
+		{"a/b", "c/d", baseAndTails{"", "a/b", "c/d"}},
+		{"a/b", "a/b/c/d", baseAndTails{"a/b", "", "c/d"}},
+		{"/a/b", "/c", baseAndTails{"/", "a/b", "c"}},
+	}
+
+	for i, d := range data {
+		base, tailA, tailB := CommonBaseAndTails(filepath.FromSlash(d.a), filepath.FromSlash(d.b))
+		if filepath.FromSlash(d.expected.base) != base {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.base, base)
+		}
+		if filepath.FromSlash(d.expected.tailA) != tailA {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.tailA, tailA)
+		}
+		if filepath.FromSlash(d.expected.tailB) != tailB {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.tailB, tailB)
+		}
+	}
+}
+
+func TestMapPaths(t *testing.T) {
+	type test struct {
+		input    string
+		expected string
+	}
+	data := []test{
+		{"content/index.md", "public/index.html"},
+		{"content/posts/first.md", "public/posts/first.html"},
+		{"content/img/logo.png", "public/img/logo.png"},
+		{"content/LICENSE", "public/LICENSE"},
+	}
+	extMap := map[string]string{"md": "html"}
+
+	var input []string
+	for _, d := range data {
+		input = append(input, filepath.FromSlash(d.input))
+	}
+	res, err := MapPaths(input, "content", "public", extMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, d := range data {
+		if filepath.FromSlash(d.expected) != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res[i])
+		}
+	}
+
+	if _, err := MapPaths([]string{filepath.FromSlash("static/a.css")}, "content", "public", extMap); err == nil {
+		t.Error("Expected error for path outside of source root")
+	}
+	if _, err := MapPaths([]string{filepath.FromSlash("contents/a.md")}, "content", "public", extMap); err == nil {
+		t.Error("Expected error for path sharing only a name prefix with source root")
+	}
+}
+
+func TestTrimPathPrefix(t *testing.T) {
+	type trimResult struct {
+		rest    string
+		matched bool
+	}
+	type test struct {
+		path     string
+		prefix   string
+		expected trimResult
+	}
+	data := []test{
+		{"a/b/c", "a/b", trimResult{"c", true}},
+		{"a/b/c", "a/b/", trimResult{"c", true}},
+		{"/a/b/c", "/a", trimResult{"b/c", true}},
+		{"/a/b", "/", trimResult{"a/b", true}},
+		{"a/bc", "a/b", trimResult{"a/bc", false}},
+		{"a/b", "a/b", trimResult{"", true}},
+		{"a/b/", "a/b", trimResult{"", true}},
+		{"a/b", "c", trimResult{"a/b", false}},
+		{"a/b", "a/b/c", trimResult{"a/b", false}},
+		{"a/b", ".", trimResult{"a/b", true}},
+	}
+
+	for i, d := range data {
+		rest, matched := TrimPathPrefix(filepath.FromSlash(d.path), filepath.FromSlash(d.prefix))
+		if filepath.FromSlash(d.expected.rest) != rest {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.rest, rest)
+		}
+		if d.expected.matched != matched {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected.matched, matched)
+		}
+	}
+}
+
+func TestResolveAgainst(t *testing.T) {
+	type test struct {
+		cwd      string
+		path     string
+		expected string
+	}
+	data := []test{
+		{"/home/user", "data/run001.mod", "/home/user/data/run001.mod"},
+		{"/home/user", "/etc/config", "/etc/config"},
+		{"/home/user", "/etc/../var/./log", "/var/log"},
+		{"/home/user", "../data/./run001.mod", "/home/data/run001.mod"},
+		{"/home/user", ".", "/home/user"},
+		{"/home/user", "../../..", "/"},
+		{"/home/user/", "", "/home/user"},
+	}
+
+	for i, d := range data {
+		res := ResolveAgainst(filepath.FromSlash(d.cwd), filepath.FromSlash(d.path))
+		if filepath.FromSlash(d.expected) != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestRelativeLink(t *testing.T) {
+	type test struct {
+		from      string
+		to        string
+		trimIndex bool
+		expected  string
+	}
+	data := []test{
+		{"docs/intro.html", "docs/usage.html", false, "./usage.html"},
+		{"docs/intro.html", "docs/guide/setup.html", false, "./guide/setup.html"},
+		{"docs/guide/setup.html", "docs/intro.html", false, "../intro.html"},
+		{"docs/guide/deep/page.html", "docs/api/v1/ref.html", false, "../../api/v1/ref.html"},
+		{"docs/guide/intro.html", "docs/api/index.html", false, "../api/index.html"},
+		{"docs/guide/intro.html", "docs/api/index.html", true, "../api/"},
+		{"docs/guide/intro.html", "docs/guide/index.html", true, "./"},
+		{"docs/guide/intro.html", "docs/index.html", true, "../"},
+		{"index.html", "about.html", false, "./about.html"},
+		{"docs/a.html", "docs", false, "./"},
+		{"docs/a.html", "docs/", false, "./"},
+		{"docs/a.html", "docs/guide/", false, "./guide/"},
+	}
+
+	for i, d := range data {
+		res, err := RelativeLink(d.from, d.to, d.trimIndex)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestRelLexical(t *testing.T) {
+	type test struct {
+		base     string
+		target   string
+		expected string
+		// what GetRelativePath(target, base) returns, to contrast behavior
+		getRelative string
+		err         bool
+	}
+	data := []test{
+		{"a/b", "a/b/c/d", "c/d", "c/d", false},
+		{"a/b", "a/b/c/", "c", "c/", false},
+		{"a/b/c", "a/d", "../../d", "../../d", false},
+		{"/a", "/a/./b/../c", "c", "c", false},
+		{"a", "a", ".", ".", false},
+		{"", "a/b", "a/b", "a/b", false},
+		{"", "/a/b", "", "", true},
+		{"/a", "b", "", "", true},
+		{"../a", "b", "", "", true},
+	}
+
+	for i, d := range data {
+		res, err := RelLexical(filepath.FromSlash(d.base), filepath.FromSlash(d.target))
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+		rel, _ := GetRelativePath(filepath.FromSlash(d.target), filepath.FromSlash(d.base))
+		if filepath.FromSlash(d.getRelative) != rel {
+			t.Errorf("Test %d failed. Expected GetRelativePath %s got %s", i, d.getRelative, rel)
+		}
+	}
+}
+
+func TestRebaseAll(t *testing.T) {
+	type test struct {
+		input    []string
+		base     string
+		expected []string
+	}
+	data := []test{
+		{
+			[]string{"/data/a/run001.mod", "/data/b/run002.mod", "/data/a/deep/run003.mod"},
+			"/data",
+			[]string{"a/run001.mod", "b/run002.mod", "a/deep/run003.mod"},
+		},
+		{
+			[]string{"/data/a/run001.mod"},
+			"/data/a",
+			[]string{"run001.mod"},
+		},
+		{
+			[]string{"/data/a", "/data/a/run001.mod"},
+			"/data",
+			[]string{"a", "a/run001.mod"},
+		},
+		{
+			[]string{"a/run001.mod", "b/run002.mod"},
+			".",
+			[]string{"a/run001.mod", "b/run002.mod"},
+		},
+	}
+
+	for i, d := range data {
+		var input []string
+		for _, p := range d.input {
+			input = append(input, filepath.FromSlash(p))
+		}
+		base, rebased, err := RebaseAll(input)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if filepath.FromSlash(d.base) != base {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.base, base)
+		}
+		if len(rebased) != len(d.expected) {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, rebased)
+			continue
+		}
+		for j, e := range d.expected {
+			if filepath.FromSlash(e) != rebased[j] {
+				t.Errorf("Test %d failed. Expected %s got %s", i, e, rebased[j])
+			}
+		}
+	}
+
+	if _, _, err := RebaseAll(nil); err == nil {
+		t.Error("Expected error for no paths")
+	}
+}
+
+func TestSafeJoin(t *testing.T) {
+	type test struct {
+		base      string
+		untrusted string
+		expected  string
+		err       bool
+	}
+	data := []test{
+		{"/dst", "a/b/c.txt", "/dst/a/b/c.txt", false},
+		{"/dst", "a/../b.txt", "/dst/b.txt", false},
+		{"/dst", "./a.txt", "/dst/a.txt", false},
+		{"/dst", "a/b/../../c.txt", "/dst/c.txt", false},
+		{"/dst", "..a.txt", "/dst/..a.txt", false},
+		{"/dst", ".", "/dst", false},
+		{"/dst", "../evil.txt", "", true},
+		{"/dst", "a/../../evil.txt", "", true},
+		{"/dst", "../dst2/evil.txt", "", true},
+		{"/dst", "/etc/passwd", "", true},
+		{"dst", "../../evil.txt", "", true},
+	}
+
+	for i, d := range data {
+		res, err := SafeJoin(filepath.FromSlash(d.base), filepath.FromSlash(d.untrusted))
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if filepath.FromSlash(d.expected) != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestPathSortKey(t *testing.T) {
+	expected := []string{
+		"a",
+		"a/b",
+		"a/b/c",
+		"a/b/c/d.txt",
+		"a/b-c",
+		"a/b.txt",
+		"a-b/c",
+		"b",
+	}
+	shuffled := []string{
+		`a\b-c`,
+		"b",
+		"a/b/c/d.txt",
+		"a-b/c",
+		`a\b`,
+		"a/b.txt",
+		"./a",
+		`a/b\c`,
+	}
+
+	sort.Slice(shuffled, func(i, j int) bool {
+		return PathSortKey(shuffled[i]) < PathSortKey(shuffled[j])
+	})
+	for i, e := range expected {
+		if PathSortKey(e) != PathSortKey(shuffled[i]) {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, shuffled[i])
+		}
+	}
+	if PathSortKey(`a\b/c`) != PathSortKey("a/b/c") {
+		t.Error("Expected separator styles to produce the same key")
+	}
+}
+
+func TestTarName(t *testing.T) {
+	type test struct {
+		root     string
+		path     string
+		expected string
+		err      bool
+	}
+	data := []test{
+		{"/src", "/src/main.go", "main.go", false},
+		{"/src", "/src/docs/intro.md", "docs/intro.md", false},
+		{"/src", "/src/docs/api/v1/ref.md", "docs/api/v1/ref.md", false},
+		{"/src", "/src/docs/", "docs/", false},
+		{"/src", "/src/docs/api/", "docs/api/", false},
+		{"/src/", "/src/./docs//api/", "docs/api/", false},
+		{"src", "src/main.go", "main.go", false},
+		{".", "main.go", "main.go", false},
+		{".", "./docs/", "docs/", false},
+		{"/src", "/src", "", true},
+		{"/src", "/src/", "", true},
+		{"/src", "/other/main.go", "", true},
+		{"/src", "/src/../main.go", "", true},
+		{"/src", "src/main.go", "", true},
+	}
+
+	for i, d := range data {
+		res, err := TarName(filepath.FromSlash(d.root), filepath.FromSlash(d.path))
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestMostCommonDir(t *testing.T) {
+	type test struct {
+		input    []string
+		expected string
+	}
+	data := []test{
+		{[]string{"/proj/src/a.go", "/proj/src/pkg/b.go", "/proj/go.mod", "/tmp/x.go"}, "/proj"},
+		{[]string{"/proj/src/a.go", "/proj/src/b.go", "/proj/src/pkg/c.go", "/home/user/notes.txt"}, "/proj/src"},
+		{[]string{"/proj/src/a.go", "/proj/src/b.go", "/proj/src/c.go"}, "/proj/src"},
+		{[]string{"/proj/src/a.go"}, "/proj/src"},
+		{[]string{"docs/a.md", "docs/b.md", "README.md"}, "docs"},
+		{[]string{"a/x.txt", "b/y.txt"}, "."},
+		{[]string{"/a/x.txt", "/b/y.txt"}, "/"},
+		// an even split has no majority below the root
+		{[]string{"/a/x.txt", "/a/y.txt", "/b/x.txt", "/b/y.txt"}, "/"},
+		{[]string{"/a/x.txt", "b/y.txt"}, ""},
+		{nil, ""},
+	}
+
+	for i, d := range data {
+		var paths []string
+		for _, p := range d.input {
+			paths = append(paths, filepath.FromSlash(p))
+		}
+		res := MostCommonDir(paths)
+		if filepath.FromSlash(d.expected) != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	type test struct {
+		input    []string
+		foldCase bool
+		expected string
+	}
+	data := []test{
+		{[]string{"docs/intro.md", "./docs/intro.md", "docs//intro.md", "docs/api/../intro.md", "docs/./intro.md"}, false, "docs/intro.md"},
+		{[]string{"/srv/site/", "/srv/site", "/srv//site/."}, false, "/srv/site"},
+		{[]string{"Docs/Intro.md", "docs/INTRO.md", "./docs/intro.MD"}, true, "docs/intro.md"},
+		{[]string{"", ".", "./"}, false, "."},
+	}
+	for i, d := range data {
+		for _, p := range d.input {
+			if res := CacheKey(filepath.FromSlash(p), d.foldCase); d.expected != res {
+				t.Errorf("Test %d failed. Expected %s got %s for %s", i, d.expected, res, p)
+			}
+		}
+	}
+
+	distinct := []string{"docs/intro.md", "Docs/intro.md", "docs/intro.md/x", "docs/intro", "/docs/intro.md", "docs-intro.md"}
+	seen := make(map[string]string)
+	for _, p := range distinct {
+		key := CacheKey(filepath.FromSlash(p), false)
+		if other, ok := seen[key]; ok {
+			t.Errorf("Expected %s and %s to have distinct keys, both got %s", p, other, key)
+		}
+		seen[key] = p
+	}
+}
+
+func TestRelBetween(t *testing.T) {
+	type test struct {
+		a        string
+		b        string
+		expected string
+	}
+	data := []test{
+		{"docs/api/index.md", "docs/intro.md", "../intro.md"},
+		{"docs/intro.md", "docs/api/index.md", "api/index.md"},
+		{"docs/intro.md", "docs/setup.md", "setup.md"},
+		{"/site/a/b/page.html", "/site/c/d.css", "../../c/d.css"},
+		{"index.md", "docs/intro.md", "docs/intro.md"},
+		{"docs/intro.md", "docs", "."},
+		// unrelated paths fall back to b
+		{"/site/page.html", "docs/./intro.md", "docs/intro.md"},
+		{"docs/page.html", "/site/intro.md", "/site/intro.md"},
+		{"../../up/page.html", "docs/intro.md", "docs/intro.md"},
+	}
+
+	for i, d := range data {
+		res := RelBetween(filepath.FromSlash(d.a), filepath.FromSlash(d.b))
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestStripDotSegments(t *testing.T) {
+	type test struct {
+		input    string
+		expected string
+	}
+	data := []test{
+		{"./foo/./bar", "foo/bar"},
+		{"foo/./bar/.", "foo/bar"},
+		{"foo//bar/", "foo/bar"},
+		{"/./foo/bar", "/foo/bar"},
+		{"link/../sibling", "link/../sibling"},
+		{"./a/./../b", "a/../b"},
+		{"../up/./file.txt", "../up/file.txt"},
+		{"/", "/"},
+		{"./", "."},
+		{".", "."},
+		{"", "."},
+	}
+
+	for i, d := range data {
+		res := StripDotSegments(filepath.FromSlash(d.input))
+		if filepath.FromSlash(d.expected) != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestRelOrAbs(t *testing.T) {
+	type test struct {
+		base     string
+		target   string
+		maxUp    int
+		expected string
+	}
+	data := []test{
+		{"/site/a", "/site/a/b/page.html", 0, "b/page.html"},
+		{"/site/a/b", "/site/a/c.css", 2, "../c.css"},
+		{"/site/a/b", "/site/c.css", 2, "../../c.css"},
+		{"/site/a/b/c", "/site/c.css", 2, "/site/c.css"},
+		{"/site/a/b/c/d", "/site/c.css", 3, "/site/c.css"},
+		{"/site/a/b", "/site/a/b", 0, "."},
+		{"docs/api", "docs/intro.md", 1, "../intro.md"},
+		{"docs/api", "img/logo.png", 1, "img/logo.png"},
+		{"docs/api", "./img/../img/logo.png", 2, "../../img/logo.png"},
+		// unrelated paths fall back to target
+		{"/site", "docs/./intro.md", 5, "docs/intro.md"},
+	}
+
+	for i, d := range data {
+		res := RelOrAbs(filepath.FromSlash(d.base), filepath.FromSlash(d.target), d.maxUp)
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
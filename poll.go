@@ -0,0 +1,97 @@
+package goutils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// fileState is the part of a file's metadata compared between polls
+type fileState struct {
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// PollChanges snapshots the tree at root every interval and sends the sorted
+// relative paths of any added, removed or modified files on the returned
+// channel whenever a snapshot differs from the previous one.
+// Unlike fsnotify this works on any afero filesystem, including MemMapFs.
+// The channel is closed once ctx is canceled. An error is only returned if
+// the initial snapshot cannot be taken, failed snapshots while polling are
+// skipped and retried on the next tick.
+func PollChanges(ctx context.Context, fs afero.Fs, root string, interval time.Duration) (<-chan []string, error) {
+	prev, err := snapshotTree(fs, root)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(chan []string)
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			cur, err := snapshotTree(fs, root)
+			if err != nil {
+				continue
+			}
+			changed := diffSnapshots(prev, cur)
+			prev = cur
+			if len(changed) == 0 {
+				continue
+			}
+			select {
+			case changes <- changed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// snapshotTree records the state of every file below root keyed by relative path
+func snapshotTree(fs afero.Fs, root string) (map[string]fileState, error) {
+	snapshot := make(map[string]fileState)
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		snapshot[rel] = fileState{info.Size(), info.Mode(), info.ModTime()}
+		return nil
+	})
+	return snapshot, err
+}
+
+// diffSnapshots returns the sorted paths that differ between two snapshots
+func diffSnapshots(prev, cur map[string]fileState) []string {
+	var changed []string
+	for path, state := range cur {
+		if old, ok := prev[path]; !ok || old.size != state.size || old.mode != state.mode || !old.modTime.Equal(state.modTime) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := cur[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
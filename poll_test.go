@@ -0,0 +1,66 @@
+package goutils
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// waitForChange reads from changes until a batch containing path arrives
+func waitForChange(t *testing.T, changes <-chan []string, path string) {
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case changed, ok := <-changes:
+			if !ok {
+				t.Fatalf("Expected change to %s but channel closed", path)
+			}
+			for _, c := range changed {
+				if c == path {
+					return
+				}
+			}
+		case <-timeout:
+			t.Fatalf("Expected change to %s but timed out", path)
+		}
+	}
+}
+
+func TestPollChanges(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	root := filepath.FromSlash("/root")
+	afero.WriteFile(fs, filepath.Join(root, "a.txt"), []byte("a"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := PollChanges(ctx, fs, root, 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	afero.WriteFile(fs, filepath.Join(root, "sub", "b.txt"), []byte("b"), 0644)
+	waitForChange(t, changes, filepath.Join("sub", "b.txt"))
+
+	afero.WriteFile(fs, filepath.Join(root, "a.txt"), []byte("modified"), 0644)
+	waitForChange(t, changes, "a.txt")
+
+	fs.Remove(filepath.Join(root, "a.txt"))
+	waitForChange(t, changes, "a.txt")
+
+	cancel()
+	select {
+	case _, ok := <-changes:
+		for ok {
+			_, ok = <-changes
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected channel to close after cancel")
+	}
+
+	if _, err := PollChanges(context.Background(), fs, "/missing", time.Millisecond); err == nil {
+		t.Error("Expected error for missing root")
+	}
+}
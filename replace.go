@@ -0,0 +1,64 @@
+package goutils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ReplaceInFiles walks root and replaces all occurrences of oldStr with newStr
+// in files ending with one of exts, or in all files if no exts are given.
+// Only files whose content changes are written back, atomically, and their
+// paths are returned.
+//  ReplaceInFiles(fs, "src", "goutils.Old", "goutils.New", ".go")
+func ReplaceInFiles(fs afero.Fs, root string, oldStr, newStr string, exts ...string) (changed []string, err error) {
+	if oldStr == "" {
+		return nil, errors.New("text to replace must not be empty")
+	}
+	err = afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if !hasAnySuffix(path, exts) {
+			return nil
+		}
+		content, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		replaced := bytes.Replace(content, []byte(oldStr), []byte(newStr), -1)
+		if bytes.Equal(replaced, content) {
+			return nil
+		}
+		err = writeAtomic(fs, path, func(w io.Writer) error {
+			_, err := w.Write(replaced)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		changed = append(changed, path)
+		return nil
+	})
+	return changed, err
+}
+
+// hasAnySuffix checks if s ends with any of suffixes, or if none are given
+func hasAnySuffix(s string, suffixes []string) bool {
+	if len(suffixes) == 0 {
+		return true
+	}
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package goutils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+func TestReplaceInFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"/src/a.go":        "goutils.Old()",
+		"/src/pkg/b.go":    "x := goutils.Old(goutils.Old())",
+		"/src/pkg/c.go":    "nothing to see",
+		"/src/README.md":   "call goutils.Old()",
+		"/src/pkg/notes.t": "goutils.Old",
+	}
+	past := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	for path, content := range files {
+		afero.WriteFile(fs, filepath.FromSlash(path), []byte(content), 0644)
+		fs.Chtimes(filepath.FromSlash(path), past, past)
+	}
+
+	changed, err := ReplaceInFiles(fs, filepath.FromSlash("/src"), "goutils.Old", "goutils.New", ".go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedChanged := []string{"/src/a.go", "/src/pkg/b.go"}
+	if len(changed) != len(expectedChanged) {
+		t.Fatalf("Expected %v got %v", expectedChanged, changed)
+	}
+	for i, e := range expectedChanged {
+		if filepath.FromSlash(e) != changed[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, changed[i])
+		}
+	}
+
+	expected := map[string]string{
+		"/src/a.go":        "goutils.New()",
+		"/src/pkg/b.go":    "x := goutils.New(goutils.New())",
+		"/src/pkg/c.go":    "nothing to see",
+		"/src/README.md":   "call goutils.Old()",
+		"/src/pkg/notes.t": "goutils.Old",
+	}
+	for path, e := range expected {
+		res, _ := afero.ReadFile(fs, filepath.FromSlash(path))
+		if e != string(res) {
+			t.Errorf("Expected %s to contain %s got %s", path, e, res)
+		}
+	}
+	for _, path := range []string{"/src/pkg/c.go", "/src/README.md", "/src/pkg/notes.t"} {
+		info, _ := fs.Stat(filepath.FromSlash(path))
+		if !info.ModTime().Equal(past) {
+			t.Errorf("Expected %s not to be rewritten", path)
+		}
+	}
+
+	changed, _ = ReplaceInFiles(fs, filepath.FromSlash("/src"), "goutils.Old", "goutils.New")
+	if len(changed) != 2 {
+		t.Errorf("Expected remaining files to change without extension filter got %v", changed)
+	}
+	fs.Chtimes(filepath.FromSlash("/src/a.go"), past, past)
+	changed, _ = ReplaceInFiles(fs, filepath.FromSlash("/src"), "goutils.New", "goutils.New")
+	if len(changed) != 0 {
+		t.Errorf("Expected no files to change when replacing text with itself got %v", changed)
+	}
+	if info, _ := fs.Stat(filepath.FromSlash("/src/a.go")); !info.ModTime().Equal(past) {
+		t.Error("Expected /src/a.go not to be rewritten")
+	}
+	if _, err := ReplaceInFiles(fs, filepath.FromSlash("/src"), "", "x"); err == nil {
+		t.Error("Expected error for empty search text")
+	}
+}
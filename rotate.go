@@ -0,0 +1,106 @@
+package goutils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// rotatingWriter appends to a file, rotating it once it would grow too large
+type rotatingWriter struct {
+	mu       sync.Mutex
+	fs       afero.Fs
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     afero.File
+	size     int64
+}
+
+// NewRotatingWriter returns a writer appending to the file at path. Before a
+// write would take the file beyond maxSize bytes, path is renamed to path.1,
+// an existing path.1 to path.2 and so on, and a new empty file is started.
+// At most maxFiles of these archives are kept, path.1 being the most recent,
+// and older ones are deleted. A single write is never split, so a write
+// larger than maxSize still goes whole into a fresh file.
+func NewRotatingWriter(fs afero.Fs, path string, maxSize int64, maxFiles int) (io.WriteCloser, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("max size must be positive, got %d", maxSize)
+	}
+	if maxFiles < 0 {
+		return nil, fmt.Errorf("max files must not be negative, got %d", maxFiles)
+	}
+	w := &rotatingWriter{fs: fs, path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := w.open(os.O_APPEND); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingWriter) open(flag int) error {
+	f, err := w.fs.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|flag, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file, w.size = f, info.Size()
+	return nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the archives up by one, dropping the oldest, and starts a
+// new file at path
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+	archive := func(i int) string { return fmt.Sprintf("%s.%d", w.path, i) }
+	if w.maxFiles > 0 {
+		if err := w.fs.Remove(archive(w.maxFiles)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for i := w.maxFiles - 1; i >= 1; i-- {
+			if err := w.fs.Rename(archive(i), archive(i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := w.fs.Rename(w.path, archive(1)); err != nil {
+			return err
+		}
+	}
+	return w.open(os.O_TRUNC)
+}
+
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return os.ErrClosed
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
@@ -0,0 +1,84 @@
+package goutils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestRotatingWriter(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	fs.MkdirAll("/logs", 0755)
+	afero.WriteFile(fs, "/logs/app.log", []byte("old\n"), 0644)
+
+	w, err := NewRotatingWriter(fs, "/logs/app.log", 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each line is 4 bytes, so two fit in a file before it rotates and the
+	// existing content with the first line ends up beyond the limit
+	for i := 1; i <= 7; i++ {
+		if _, err := fmt.Fprintf(w, "%03d\n", i); err != nil {
+			t.Fatalf("Unexpected error writing line %d: %s", i, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	type test struct {
+		path     string
+		expected string
+	}
+	data := []test{
+		{"/logs/app.log", "006\n007\n"},
+		{"/logs/app.log.1", "004\n005\n"},
+		{"/logs/app.log.2", "002\n003\n"},
+	}
+	for i, d := range data {
+		res, err := afero.ReadFile(fs, d.path)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != string(res) {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected, res)
+		}
+	}
+	files, _ := afero.ReadDir(fs, "/logs")
+	if len(files) != len(data) {
+		t.Errorf("Expected %d files got %v", len(data), ListFiles(files))
+	}
+
+	if _, err := w.Write([]byte("late")); err == nil {
+		t.Error("Expected error writing after close")
+	}
+}
+
+func TestRotatingWriterNoArchives(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	// not an archive, so rotating must leave it alone
+	afero.WriteFile(fs, "app.log.0", []byte("unrelated"), 0644)
+	w, err := NewRotatingWriter(fs, "app.log", 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("first"))
+	w.Write([]byte("next"))
+	w.Close()
+	res, _ := afero.ReadFile(fs, "app.log")
+	if string(res) != "next" {
+		t.Errorf("Expected next got %s", res)
+	}
+	if exists, _ := Exists(fs, "app.log.1"); exists {
+		t.Error("Expected no archive to be kept")
+	}
+	if content, _ := afero.ReadFile(fs, "app.log.0"); string(content) != "unrelated" {
+		t.Errorf("Expected app.log.0 to be kept got %q", content)
+	}
+
+	if _, err := NewRotatingWriter(fs, "app.log", 0, 1); err == nil || !strings.Contains(err.Error(), "max size") {
+		t.Errorf("Expected max size error got %v", err)
+	}
+}
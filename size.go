@@ -0,0 +1,69 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// BucketBySize groups fileinfo into buckets of ascending size, where bucket i
+// holds the entries smaller than boundaries[i] and not placed in a prior bucket.
+// The final bucket holds everything at or above the last boundary, so
+// len(boundaries)+1 buckets are always returned.
+//  BucketBySize(infos, []int64{1 << 10, 1 << 20}) --> <1KB, <1MB, rest
+func BucketBySize(infos []os.FileInfo, boundaries []int64) [][]os.FileInfo {
+	buckets := make([][]os.FileInfo, len(boundaries)+1)
+	for _, info := range infos {
+		b := len(boundaries)
+		for i, boundary := range boundaries {
+			if info.Size() < boundary {
+				b = i
+				break
+			}
+		}
+		buckets[b] = append(buckets[b], info)
+	}
+	return buckets
+}
+
+// TotalSize sums the size of the regular files in an array of fileinfo,
+// such as an already read directory. Directories are skipped.
+func TotalSize(infos []os.FileInfo) int64 {
+	var total int64
+	for _, info := range infos {
+		if !info.IsDir() && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// UsageByRoot walks root and totals the size of the files below each of its
+// immediate subdirectories, found as with ExtractRootPaths, keyed by the
+// subdirectory name. Files directly in root are totaled under "".
+func UsageByRoot(fs afero.Fs, root string) (map[string]int64, error) {
+	usage := make(map[string]int64)
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		key := ""
+		if filepath.Dir(rel) != "." {
+			key = ExtractRootPaths([]string{rel})[0]
+		}
+		usage[key] += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
@@ -0,0 +1,122 @@
+package goutils
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// fakeFileInfo is a minimal os.FileInfo for tests that operate on
+// already read directory entries
+type fakeFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (f fakeFileInfo) Name() string       { return f.name }
+func (f fakeFileInfo) Size() int64        { return f.size }
+func (f fakeFileInfo) Mode() os.FileMode  { return f.mode }
+func (f fakeFileInfo) ModTime() time.Time { return f.modTime }
+func (f fakeFileInfo) IsDir() bool        { return f.mode.IsDir() }
+func (f fakeFileInfo) Sys() interface{}   { return nil }
+
+func TestBucketBySize(t *testing.T) {
+	infos := []os.FileInfo{
+		fakeFileInfo{name: "empty", size: 0},
+		fakeFileInfo{name: "small", size: 512},
+		fakeFileInfo{name: "onekb", size: 1024},
+		fakeFileInfo{name: "medium", size: 4096},
+		fakeFileInfo{name: "onemb", size: 1 << 20},
+		fakeFileInfo{name: "large", size: 1 << 30},
+	}
+	expected := [][]string{
+		{"empty", "small"},
+		{"onekb", "medium"},
+		{"onemb", "large"},
+	}
+
+	res := BucketBySize(infos, []int64{1 << 10, 1 << 20})
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %d buckets got %d", len(expected), len(res))
+	}
+	for i, bucket := range res {
+		if len(bucket) != len(expected[i]) {
+			t.Errorf("Test %d failed. Expected %v got %d entries", i, expected[i], len(bucket))
+			continue
+		}
+		for j, info := range bucket {
+			if expected[i][j] != info.Name() {
+				t.Errorf("Test %d failed. Expected %s got %s", i, expected[i][j], info.Name())
+			}
+		}
+	}
+
+	if res := BucketBySize(infos, nil); len(res) != 1 || len(res[0]) != len(infos) {
+		t.Errorf("Expected a single bucket with all entries got %v", res)
+	}
+}
+
+func TestTotalSize(t *testing.T) {
+	type test struct {
+		input    []os.FileInfo
+		expected int64
+	}
+	data := []test{
+		{
+			[]os.FileInfo{
+				fakeFileInfo{name: "a", size: 10},
+				fakeFileInfo{name: "dir", size: 4096, mode: os.ModeDir},
+				fakeFileInfo{name: "b", size: 1 << 32},
+				fakeFileInfo{name: "link", size: 20, mode: os.ModeSymlink},
+			},
+			10 + 1<<32,
+		},
+		{[]os.FileInfo{fakeFileInfo{name: "dir", size: 4096, mode: os.ModeDir}}, 0},
+		{nil, 0},
+	}
+
+	for i, d := range data {
+		res := TotalSize(d.input)
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %d got %d", i, d.expected, res)
+		}
+	}
+}
+
+func TestUsageByRoot(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	files := map[string]int{
+		"/data/top.txt":               10,
+		"/data/.hidden":               5,
+		"/data/logs/a.log":            100,
+		"/data/logs/archive/b.log":    200,
+		"/data/models/run001.mod":     30,
+		"/data/models/run001/run.lst": 40,
+	}
+	for path, size := range files {
+		afero.WriteFile(fs, path, make([]byte, size), 0644)
+	}
+	fs.MkdirAll("/data/empty", 0755)
+	expected := map[string]int64{
+		"":       15,
+		"logs":   300,
+		"models": 70,
+	}
+
+	res, err := UsageByRoot(fs, "/data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Errorf("Expected %v got %v", expected, res)
+	}
+	for key, e := range expected {
+		if e != res[key] {
+			t.Errorf("Expected %s to total %d got %d", key, e, res[key])
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package goutils
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// SplitFile streams the file at path into outDir as chunks of at most
+// chunkSize bytes, numbered after the file name, and returns the chunk paths
+// in order. Only the last chunk may be smaller, an empty file gives a single
+// empty chunk.
+//  run.tar --> out/run.tar.001, out/run.tar.002, out/run.tar.003
+func SplitFile(fs afero.Fs, path string, chunkSize int64, outDir string) ([]string, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+	in, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.MkdirAll(outDir, 0755); err != nil {
+		return nil, err
+	}
+	var chunks []string
+	for remaining := info.Size(); len(chunks) == 0 || remaining > 0; remaining -= chunkSize {
+		chunk := filepath.Join(outDir, fmt.Sprintf("%s.%03d", filepath.Base(path), len(chunks)+1))
+		out, err := fs.Create(chunk)
+		if err != nil {
+			return chunks, err
+		}
+		_, err = io.CopyN(out, in, chunkSize)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		chunks = append(chunks, chunk)
+		if err != nil && err != io.EOF {
+			return chunks, err
+		}
+	}
+	return chunks, nil
+}
+
+// JoinFiles streams the chunks at chunkPaths, in order, into dst, restoring
+// a file split by SplitFile. dst is only replaced once every chunk has been
+// copied, and an error names the chunk that could not be read.
+func JoinFiles(fs afero.Fs, chunkPaths []string, dst string) error {
+	if err := fs.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return writeAtomic(fs, dst, func(w io.Writer) error {
+		for i, chunk := range chunkPaths {
+			if err := appendChunk(fs, chunk, w); err != nil {
+				return fmt.Errorf("chunk %d (%s): %s", i+1, chunk, err)
+			}
+		}
+		return nil
+	})
+}
+
+func appendChunk(fs afero.Fs, chunk string, w io.Writer) error {
+	f, err := fs.Open(chunk)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,96 @@
+package goutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestSplitFile(t *testing.T) {
+	type test struct {
+		size      int
+		chunkSize int64
+		expected  []int
+	}
+	data := []test{
+		{10, 4, []int{4, 4, 2}},
+		{12, 4, []int{4, 4, 4}},
+		{3, 4, []int{3}},
+		{0, 4, []int{0}},
+		{5, 1, []int{1, 1, 1, 1, 1}},
+	}
+
+	for i, d := range data {
+		fs := afero.NewMemMapFs()
+		content := bytes.Repeat([]byte("abcdefghij"), 2)[:d.size]
+		afero.WriteFile(fs, filepath.FromSlash("/in/data.bin"), content, 0644)
+		res, err := SplitFile(fs, filepath.FromSlash("/in/data.bin"), d.chunkSize, filepath.FromSlash("/out"))
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+			continue
+		}
+		if len(res) != len(d.expected) {
+			t.Errorf("Test %d failed. Expected %d chunks got %v", i, len(d.expected), res)
+			continue
+		}
+		var joined []byte
+		for j, chunk := range res {
+			expectedPath := filepath.FromSlash(fmt.Sprintf("/out/data.bin.%03d", j+1))
+			if chunk != expectedPath {
+				t.Errorf("Test %d failed. Expected %s got %s", i, expectedPath, chunk)
+			}
+			b, _ := afero.ReadFile(fs, chunk)
+			if len(b) != d.expected[j] {
+				t.Errorf("Test %d failed. Expected chunk %d to be %d bytes got %d", i, j, d.expected[j], len(b))
+			}
+			joined = append(joined, b...)
+		}
+		if !bytes.Equal(content, joined) {
+			t.Errorf("Test %d failed. Expected chunks to reproduce %q got %q", i, content, joined)
+		}
+	}
+
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "data.bin", []byte("data"), 0644)
+	if _, err := SplitFile(fs, "data.bin", 0, "out"); err == nil {
+		t.Error("Expected error for a zero chunk size")
+	}
+	if _, err := SplitFile(fs, "missing.bin", 4, "out"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestJoinFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	afero.WriteFile(fs, filepath.FromSlash("/in/data.bin"), content, 0644)
+	chunks, err := SplitFile(fs, filepath.FromSlash("/in/data.bin"), 64, filepath.FromSlash("/chunks"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.FromSlash("/restored/data.bin")
+	if err := JoinFiles(fs, chunks, dst); err != nil {
+		t.Fatal(err)
+	}
+	original, _ := Checksum(fs, filepath.FromSlash("/in/data.bin"), sha256.New())
+	restored, _ := Checksum(fs, dst, sha256.New())
+	if original != restored {
+		t.Errorf("Expected restored digest %s got %s", original, restored)
+	}
+
+	// a missing chunk is named and leaves the existing dst alone
+	fs.Remove(chunks[2])
+	err = JoinFiles(fs, chunks, dst)
+	if err == nil || !strings.Contains(err.Error(), "chunk 3") || !strings.Contains(err.Error(), chunks[2]) {
+		t.Errorf("Expected error naming chunk 3 got %v", err)
+	}
+	if after, _ := Checksum(fs, dst, sha256.New()); after != original {
+		t.Errorf("Expected failed join to keep %s got %s", original, after)
+	}
+}
@@ -0,0 +1,316 @@
+package goutils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// RelativizeSymlinks walks root and rewrites any symlink with an absolute
+// target inside root as an equivalent relative link, so the tree stays
+// self-contained when moved or archived. Links pointing outside root are
+// left alone. The paths of the rewritten links are returned.
+func RelativizeSymlinks(fs afero.Fs, root string) ([]string, error) {
+	var rewritten []string
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return rewritten, err
+	}
+	err = afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != os.ModeSymlink {
+			return nil
+		}
+		target, err := ReadLink(fs, path)
+		if err != nil {
+			return err
+		}
+		if !filepath.IsAbs(target) || !isWithin(absRoot, target) {
+			return nil
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		relTarget, err := filepath.Rel(filepath.Dir(absPath), target)
+		if err != nil {
+			return err
+		}
+		// create the new link beside the old one and rename it into place, so
+		// the original is kept if the link cannot be created
+		tmp, err := UniquePath(fs, filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp"))
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(relTarget, tmp); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+		rewritten = append(rewritten, path)
+		return nil
+	})
+	return rewritten, err
+}
+
+// ErrSymlinkUnsupported is returned for symlink operations on filesystems,
+// such as MemMapFs, that cannot represent symlinks
+var ErrSymlinkUnsupported = errors.New("symlinks are not supported by the filesystem")
+
+// linkReader is implemented by filesystems able to read symlinks, matching
+// the afero.LinkReader interface of newer afero releases
+type linkReader interface {
+	ReadlinkIfPossible(name string) (string, error)
+}
+
+// ReadLink returns the immediate target of the symlink at path, without
+// resolving it further as GetRealPath does. Filesystems implementing
+// ReadlinkIfPossible are used directly and OsFs falls back to os.Readlink,
+// others result in ErrSymlinkUnsupported.
+func ReadLink(fs afero.Fs, path string) (string, error) {
+	switch lfs := fs.(type) {
+	case linkReader:
+		return lfs.ReadlinkIfPossible(path)
+	case *afero.OsFs:
+		return os.Readlink(path)
+	}
+	return "", &os.PathError{Op: "readlink", Path: path, Err: ErrSymlinkUnsupported}
+}
+
+// linker is implemented by filesystems able to create symlinks, matching
+// the afero.Linker interface of newer afero releases
+type linker interface {
+	SymlinkIfPossible(oldname, newname string) error
+}
+
+// Symlink creates link as a symlink pointing to target. Filesystems
+// implementing SymlinkIfPossible are used directly and OsFs falls back to
+// os.Symlink, others result in ErrSymlinkUnsupported.
+func Symlink(fs afero.Fs, target, link string) error {
+	switch lfs := fs.(type) {
+	case linker:
+		return lfs.SymlinkIfPossible(target, link)
+	case *afero.OsFs:
+		return os.Symlink(target, link)
+	}
+	return &os.LinkError{Op: "symlink", Old: target, New: link, Err: ErrSymlinkUnsupported}
+}
+
+// ValidateNoEscapingSymlinks walks root and returns the symlinks whose targets
+// resolve to a location outside of root, which would allow an extracted or
+// served tree to reach the rest of the filesystem. An empty result means the
+// tree is safe. Dangling links are checked against their literal target.
+// Links are read through fs, so filesystems that report symlinks they cannot
+// read, such as a ReadOnlyFs over the OsFs, result in ErrSymlinkUnsupported.
+func ValidateNoEscapingSymlinks(fs afero.Fs, root string) ([]string, error) {
+	var escaping []string
+	realRoot, err := resolveRoot(fs, root)
+	if err != nil {
+		return nil, err
+	}
+	err = afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != os.ModeSymlink {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		target, err := evalSymlinksFs(fs, realRoot, rel)
+		if isPathError(err, errSymlinkLoop) {
+			// a loop never resolves, so check where the link itself points
+			target, err = ReadLink(fs, path)
+			if err == nil && !filepath.IsAbs(target) {
+				target = filepath.Join(realRoot, filepath.Dir(rel), target)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if !isWithin(realRoot, target) {
+			escaping = append(escaping, path)
+		}
+		return nil
+	})
+	return escaping, err
+}
+
+// maxSymlinks is the number of links followed before giving up on a loop
+const maxSymlinks = 255
+
+var errSymlinkLoop = errors.New("too many levels of symbolic links")
+
+// lstatIfPossible returns the FileInfo of path without following a final
+// symlink on filesystems able to report links, others fall back to Stat
+func lstatIfPossible(fs afero.Fs, path string) (os.FileInfo, error) {
+	if lfs, ok := fs.(afero.Lstater); ok {
+		info, _, err := lfs.LstatIfPossible(path)
+		return info, err
+	}
+	return fs.Stat(path)
+}
+
+// evalSymlinksFs resolves the symlinks in path through fs, as
+// filepath.EvalSymlinks does on the OsFs. A relative path is taken relative
+// to base, which must already be resolved. Components that do not exist are
+// kept as they are, so a dangling link resolves to its literal target.
+func evalSymlinksFs(fs afero.Fs, base, path string) (string, error) {
+	dest := base
+	if filepath.IsAbs(path) {
+		vol := filepath.VolumeName(path)
+		dest, path = vol+FilePathSeparator, path[len(vol):]
+	}
+	parts := splitComponents(path)
+	links := 0
+	for len(parts) > 0 {
+		part := parts[0]
+		parts = parts[1:]
+		if part == "" || part == "." {
+			continue
+		}
+		if part == ".." {
+			dest = filepath.Join(dest, part)
+			continue
+		}
+		next := filepath.Join(dest, part)
+		info, err := lstatIfPossible(fs, next)
+		if os.IsNotExist(err) {
+			return filepath.Join(append([]string{next}, parts...)...), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			if !info.IsDir() {
+				// nothing can exist below a file
+				return filepath.Join(append([]string{next}, parts...)...), nil
+			}
+			dest = next
+			continue
+		}
+		if links++; links > maxSymlinks {
+			return "", &os.PathError{Op: "readlink", Path: next, Err: errSymlinkLoop}
+		}
+		target, err := ReadLink(fs, next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			vol := filepath.VolumeName(target)
+			dest, target = vol+FilePathSeparator, target[len(vol):]
+		}
+		parts = append(splitComponents(target), parts...)
+	}
+	return filepath.Clean(dest), nil
+}
+
+// resolveRoot resolves the symlinks in root through fs, only the OsFs is
+// taken relative to the working directory. The caller trusts root, so links
+// in it that fs cannot read are kept as they are.
+func resolveRoot(fs afero.Fs, root string) (string, error) {
+	if _, ok := fs.(*afero.OsFs); ok {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return "", err
+		}
+		root = abs
+	}
+	resolved, err := evalSymlinksFs(fs, "", root)
+	if isPathError(err, ErrSymlinkUnsupported) {
+		return filepath.Clean(root), nil
+	}
+	return resolved, err
+}
+
+// isPathError checks if err is an *os.PathError caused by cause
+func isPathError(err, cause error) bool {
+	pe, ok := err.(*os.PathError)
+	return ok && pe.Err == cause
+}
+
+// ResolveListing maps the name of each symlink in a directory listing, as
+// read from dir with afero.ReadDir or lstat, to the path it points to.
+// Relative targets are joined to dir, only the link itself is resolved so
+// the target of a dangling link is still reported. Other entries are omitted.
+// A link on a filesystem that cannot read it results in ErrSymlinkUnsupported.
+func ResolveListing(fs afero.Fs, fd []os.FileInfo, dir string) (map[string]string, error) {
+	targets := make(map[string]string)
+	for _, info := range fd {
+		if info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		target, err := ReadLink(fs, filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(dir, target)
+		}
+		targets[info.Name()] = target
+	}
+	return targets, nil
+}
+
+// SafeResolve resolves path, taken relative to root unless absolute, through
+// any symlinks and returns the real path only if it stays within root.
+// Paths that escape root, lexically or through a link, result in an error,
+// as do paths that do not exist. Each component is checked through fs, so a
+// link that fs reports but cannot read, as on a ReadOnlyFs over the OsFs,
+// results in ErrSymlinkUnsupported rather than being trusted.
+func SafeResolve(fs afero.Fs, root, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	if !isWithin(root, path) {
+		return "", fmt.Errorf("%s escapes %s", path, root)
+	}
+	realRoot, err := resolveRoot(fs, root)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := evalSymlinksFs(fs, realRoot, rel)
+	if err != nil {
+		return "", err
+	}
+	if _, err := fs.Stat(resolved); err != nil {
+		return "", err
+	}
+	if !isWithin(realRoot, resolved) {
+		return "", fmt.Errorf("%s resolves to %s outside of %s", path, resolved, root)
+	}
+	return resolved, nil
+}
+
+// FindBrokenSymlinks walks root and returns the symlinks whose targets
+// cannot be resolved, because the target is missing or the link is part of
+// a loop. Filesystems without symlinks, such as MemMapFs, give an empty result.
+func FindBrokenSymlinks(fs afero.Fs, root string) ([]string, error) {
+	broken := []string{}
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+		if _, err := fs.Stat(path); err != nil {
+			broken = append(broken, path)
+		}
+		return nil
+	})
+	return broken, err
+}
@@ -0,0 +1,325 @@
+package goutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// tempSymlinkDir creates a temporary directory on the OsFs and skips the
+// test if symlinks cannot be created on the platform
+func tempSymlinkDir(t *testing.T) (afero.Fs, string) {
+	fs := afero.NewOsFs()
+	dir, err := afero.TempDir(fs, "", "goutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	probe := filepath.Join(dir, "probe")
+	if err := os.Symlink(dir, probe); err != nil {
+		os.RemoveAll(dir)
+		t.Skipf("symlinks not supported: %s", err)
+	}
+	os.Remove(probe)
+	return fs, dir
+}
+
+func TestRelativizeSymlinks(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+	outside, err := afero.TempDir(fs, "", "goutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outside)
+
+	target := filepath.Join(root, "data", "file.txt")
+	fs.MkdirAll(filepath.Join(root, "data"), 0755)
+	fs.MkdirAll(filepath.Join(root, "links"), 0755)
+	afero.WriteFile(fs, target, []byte("data"), 0644)
+	inside := filepath.Join(root, "links", "inside")
+	escaping := filepath.Join(root, "links", "outside")
+	os.Symlink(target, inside)
+	os.Symlink(outside, escaping)
+
+	res, err := RelativizeSymlinks(fs, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0] != inside {
+		t.Errorf("Expected [%s] got %v", inside, res)
+	}
+
+	type test struct {
+		input    string
+		expected string
+	}
+	data := []test{
+		{inside, filepath.Join("..", "data", "file.txt")},
+		{escaping, outside},
+	}
+	for i, d := range data {
+		res, _ := os.Readlink(d.input)
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+	if content, err := afero.ReadFile(fs, inside); err != nil || string(content) != "data" {
+		t.Errorf("Expected rewritten link to resolve, got %s, %v", content, err)
+	}
+	if names, _ := afero.ReadDir(fs, filepath.Join(root, "links")); len(names) != 2 {
+		t.Errorf("Expected only the two links to remain, got %v", ListOrdered(names))
+	}
+}
+
+func TestValidateNoEscapingSymlinks(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+	outside, err := afero.TempDir(fs, "", "goutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outside)
+
+	fs.MkdirAll(filepath.Join(root, "data"), 0755)
+	afero.WriteFile(fs, filepath.Join(root, "data", "file.txt"), []byte("data"), 0644)
+	links := map[string]string{
+		"inside-abs":      filepath.Join(root, "data", "file.txt"),
+		"inside-rel":      filepath.Join("data", "file.txt"),
+		"data/up-and-in":  filepath.Join("..", "data"),
+		"outside-abs":     outside,
+		"outside-rel":     filepath.Join("..", filepath.Base(outside)),
+		"dangling-inside": "nothing-here",
+		"dangling-escape": filepath.Join("..", "..", "nothing-here"),
+		"loop":            "loop",
+	}
+	for link, target := range links {
+		os.Symlink(target, filepath.Join(root, filepath.FromSlash(link)))
+	}
+	expected := []string{"dangling-escape", "outside-abs", "outside-rel"}
+
+	res, err := ValidateNoEscapingSymlinks(fs, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %v got %v", expected, res)
+	}
+	for i, e := range expected {
+		if filepath.Join(root, e) != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, res[i])
+		}
+	}
+
+	// a wrapped OsFs reports the links but cannot read them
+	res, err = ValidateNoEscapingSymlinks(afero.NewBasePathFs(fs, root), FilePathSeparator)
+	if !isPathError(err, ErrSymlinkUnsupported) {
+		t.Errorf("Expected ErrSymlinkUnsupported got %v, %v", res, err)
+	}
+}
+
+func TestResolveListing(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+
+	fs.MkdirAll(filepath.Join(root, "data"), 0755)
+	afero.WriteFile(fs, filepath.Join(root, "data", "file.txt"), []byte("data"), 0644)
+	afero.WriteFile(fs, filepath.Join(root, "plain.txt"), []byte("plain"), 0644)
+	os.Symlink(filepath.Join(root, "data", "file.txt"), filepath.Join(root, "abs"))
+	os.Symlink(filepath.Join("data", "file.txt"), filepath.Join(root, "rel"))
+	os.Symlink("data", filepath.Join(root, "dirlink"))
+	os.Symlink("nothing-here", filepath.Join(root, "dangling"))
+	expected := map[string]string{
+		"abs":      filepath.Join(root, "data", "file.txt"),
+		"rel":      filepath.Join(root, "data", "file.txt"),
+		"dirlink":  filepath.Join(root, "data"),
+		"dangling": filepath.Join(root, "nothing-here"),
+	}
+
+	fd, err := afero.ReadDir(fs, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := ResolveListing(fs, fd, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Errorf("Expected %v got %v", expected, res)
+	}
+	for name, e := range expected {
+		if e != res[name] {
+			t.Errorf("Expected %s to resolve to %s got %s", name, e, res[name])
+		}
+	}
+
+	// a wrapped OsFs lists the links but cannot read them
+	if res, err := ResolveListing(afero.NewReadOnlyFs(fs), fd, root); !isPathError(err, ErrSymlinkUnsupported) {
+		t.Errorf("Expected ErrSymlinkUnsupported got %v, %v", res, err)
+	}
+}
+
+func TestSafeResolve(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+	outside, err := afero.TempDir(fs, "", "goutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outside)
+	realRoot, _ := filepath.EvalSymlinks(root)
+
+	fs.MkdirAll(filepath.Join(root, "data"), 0755)
+	afero.WriteFile(fs, filepath.Join(root, "data", "file.txt"), []byte("data"), 0644)
+	afero.WriteFile(fs, filepath.Join(outside, "secret.txt"), []byte("secret"), 0644)
+	os.Symlink(filepath.Join("data", "file.txt"), filepath.Join(root, "inside"))
+	os.Symlink("data", filepath.Join(root, "datalink"))
+	os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(root, "escaping"))
+	os.Symlink(outside, filepath.Join(root, "outdir"))
+
+	type test struct {
+		input    string
+		expected string
+		err      bool
+	}
+	data := []test{
+		{"data/file.txt", filepath.Join(realRoot, "data", "file.txt"), false},
+		{"inside", filepath.Join(realRoot, "data", "file.txt"), false},
+		{"datalink/file.txt", filepath.Join(realRoot, "data", "file.txt"), false},
+		{filepath.Join(root, "inside"), filepath.Join(realRoot, "data", "file.txt"), false},
+		{"escaping", "", true},
+		{"outdir/secret.txt", "", true},
+		{"../" + filepath.Base(outside) + "/secret.txt", "", true},
+		{"missing.txt", "", true},
+	}
+	for i, d := range data {
+		res, err := SafeResolve(fs, root, filepath.FromSlash(d.input))
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+
+	// a wrapped OsFs reports the links but cannot read them
+	ro := afero.NewReadOnlyFs(fs)
+	if res, err := SafeResolve(ro, realRoot, "data/file.txt"); err != nil || res != filepath.Join(realRoot, "data", "file.txt") {
+		t.Errorf("Expected %s got %s, %v", filepath.Join(realRoot, "data", "file.txt"), res, err)
+	}
+	for _, input := range []string{"escaping", "outdir/secret.txt", "inside"} {
+		if res, err := SafeResolve(ro, realRoot, filepath.FromSlash(input)); !isPathError(err, ErrSymlinkUnsupported) {
+			t.Errorf("Expected ErrSymlinkUnsupported for %s got %s, %v", input, res, err)
+		}
+	}
+
+	mem := newTestTree("/srv", []string{"index.html"})
+	if res, err := SafeResolve(mem, "/srv", "index.html"); err != nil || res != filepath.FromSlash("/srv/index.html") {
+		t.Errorf("Expected /srv/index.html got %s, %v", res, err)
+	}
+	if _, err := SafeResolve(mem, "/srv", filepath.FromSlash("../etc/passwd")); err == nil {
+		t.Error("Expected error for lexical escape")
+	}
+}
+
+func TestFindBrokenSymlinks(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+
+	fs.MkdirAll(filepath.Join(root, "data"), 0755)
+	afero.WriteFile(fs, filepath.Join(root, "data", "file.txt"), []byte("data"), 0644)
+	os.Symlink(filepath.Join("data", "file.txt"), filepath.Join(root, "valid"))
+	os.Symlink("data", filepath.Join(root, "dirlink"))
+	os.Symlink("moved.txt", filepath.Join(root, "data", "dangling"))
+	os.Symlink(filepath.Join(root, "missing"), filepath.Join(root, "dangling-abs"))
+	os.Symlink("loop", filepath.Join(root, "loop"))
+	expected := []string{
+		filepath.Join(root, "dangling-abs"),
+		filepath.Join(root, "data", "dangling"),
+		filepath.Join(root, "loop"),
+	}
+
+	res, err := FindBrokenSymlinks(fs, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %v got %v", expected, res)
+	}
+	for i, e := range expected {
+		if e != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, res[i])
+		}
+	}
+
+	mem := newTestTree("/root", []string{"a.txt"})
+	if res, err := FindBrokenSymlinks(mem, "/root"); err != nil || len(res) != 0 {
+		t.Errorf("Expected no broken links on MemMapFs got %v, %v", res, err)
+	}
+}
+
+func TestReadLink(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+
+	afero.WriteFile(fs, filepath.Join(root, "file.txt"), []byte("data"), 0644)
+	os.Symlink("file.txt", filepath.Join(root, "rel"))
+	os.Symlink(filepath.Join(root, "file.txt"), filepath.Join(root, "abs"))
+	os.Symlink("rel", filepath.Join(root, "chained"))
+	os.Symlink("missing.txt", filepath.Join(root, "dangling"))
+
+	type test struct {
+		input    string
+		expected string
+		err      bool
+	}
+	data := []test{
+		{"rel", "file.txt", false},
+		{"abs", filepath.Join(root, "file.txt"), false},
+		{"chained", "rel", false},
+		{"dangling", "missing.txt", false},
+		{"file.txt", "", true},
+	}
+	for i, d := range data {
+		res, err := ReadLink(fs, filepath.Join(root, d.input))
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+
+	mem := newTestTree("/root", []string{"a.txt"})
+	_, err := ReadLink(mem, "/root/a.txt")
+	if pe, ok := err.(*os.PathError); !ok || pe.Err != ErrSymlinkUnsupported {
+		t.Errorf("Expected ErrSymlinkUnsupported got %v", err)
+	}
+}
+
+func TestSymlink(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+
+	afero.WriteFile(fs, filepath.Join(root, "file.txt"), []byte("data"), 0644)
+	link := filepath.Join(root, "link")
+	if err := Symlink(fs, "file.txt", link); err != nil {
+		t.Fatal(err)
+	}
+	if target, _ := ReadLink(fs, link); target != "file.txt" {
+		t.Errorf("Expected link to file.txt got %s", target)
+	}
+	if content, err := afero.ReadFile(fs, link); err != nil || string(content) != "data" {
+		t.Errorf("Expected link to resolve got %s, %v", content, err)
+	}
+	if err := Symlink(fs, "file.txt", link); err == nil {
+		t.Error("Expected error when the link already exists")
+	}
+
+	mem := afero.NewMemMapFs()
+	err := Symlink(mem, "file.txt", "link")
+	if le, ok := err.(*os.LinkError); !ok || le.Err != ErrSymlinkUnsupported {
+		t.Errorf("Expected ErrSymlinkUnsupported got %v", err)
+	}
+}
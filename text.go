@@ -0,0 +1,47 @@
+package goutils
+
+import (
+	"bufio"
+	"io"
+	"unicode"
+
+	"github.com/spf13/afero"
+)
+
+// TextStats streams a file and counts its whitespace delimited words,
+// characters and lines, like wc. Characters are counted as UTF-8 runes
+// rather than bytes, and a final line without a newline is counted.
+func TextStats(fs afero.Fs, path string) (words int, chars int, lines int, err error) {
+	inFile, err := fs.Open(path)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer inFile.Close()
+	r := bufio.NewReader(inFile)
+	inWord := false
+	last := '\n'
+	for {
+		c, _, err := r.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		chars++
+		if c == '\n' {
+			lines++
+		}
+		if unicode.IsSpace(c) {
+			inWord = false
+		} else if !inWord {
+			inWord = true
+			words++
+		}
+		last = c
+	}
+	if last != '\n' {
+		lines++
+	}
+	return words, chars, lines, nil
+}
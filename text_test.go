@@ -0,0 +1,45 @@
+package goutils
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestTextStats(t *testing.T) {
+	type stats struct {
+		words int
+		chars int
+		lines int
+	}
+	type test struct {
+		input    string
+		expected stats
+	}
+	data := []test{
+		{"", stats{0, 0, 0}},
+		{"hello world\n", stats{2, 12, 1}},
+		{"hello world", stats{2, 11, 1}},
+		{"  leading and   trailing  \n\n", stats{3, 28, 2}},
+		{"one\ntwo\tthree\r\nfour", stats{4, 19, 3}},
+		{"héllo wörld\n", stats{2, 12, 1}},
+		{"日本語 テキスト\n絵文字 🎉\n", stats{4, 15, 2}},
+	}
+
+	fs := afero.NewMemMapFs()
+	for i, d := range data {
+		afero.WriteFile(fs, "file.txt", []byte(d.input), 0644)
+		words, chars, lines, err := TextStats(fs, "file.txt")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		res := stats{words, chars, lines}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+		}
+	}
+
+	if _, _, _, err := TextStats(fs, "missing.txt"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/afero"
 )
@@ -26,6 +27,23 @@ func ReplaceExtension(path string, newExt string) string {
 	return f + "." + newExt
 }
 
+// EnsureExtension appends the extension ext, given with or without its dot,
+// unless path already ends with it, ignoring case. Unlike ReplaceExtension
+// an existing different extension is kept, and an empty ext leaves path as is.
+//  report.tar --> report.tar.gz
+//  report.GZ --> report.GZ
+func EnsureExtension(path, ext string) string {
+	ext = strings.TrimPrefix(ext, ".")
+	if ext == "" {
+		return path
+	}
+	ext = "." + ext
+	if strings.HasSuffix(strings.ToLower(path), strings.ToLower(ext)) {
+		return path
+	}
+	return path + ext
+}
+
 // Filename takes a path, strips out the extension,
 // and returns the name of the file.
 func Filename(in string) (name string) {
@@ -33,6 +51,13 @@ func Filename(in string) (name string) {
 	return
 }
 
+// SplitAll takes a path and returns the directory, as given by filepath.Dir,
+// along with the name and extension - minus the dot - as given by FileAndExt.
+func SplitAll(in string) (dir, name, ext string) {
+	name, ext = FileAndExt(in)
+	return filepath.Dir(in), name, strings.TrimPrefix(ext, ".")
+}
+
 // FileAndExt returns the filename and any extension of a file path as
 // two separate strings.
 //
@@ -56,6 +81,20 @@ func FileAndExt(in string) (name string, ext string) {
 	return extractFilename(in, ext, base, FilePathSeparator), ext
 }
 
+// ExtensionIndex returns the byte index in the path, in, at which the
+// extension including the dot begins, or -1 if there is no extension.
+//  ExtensionIndex("path/run001.mod") --> 11
+// The same rules as FileAndExt apply, so the current and parent directories
+// have no extension while a dotfile such as ".bashrc" is all extension.
+func ExtensionIndex(in string) int {
+	_, ext := FileAndExt(in)
+	base := filepath.Base(in)
+	if ext == "" || base == "." || base == ".." {
+		return -1
+	}
+	return len(in) - len(ext)
+}
+
 func extractFilename(in, ext, base, pathSeparator string) (name string) {
 
 	// No file name cases. These are defined as:
@@ -97,6 +136,63 @@ func GetRelativePath(path, base string) (final string, err error) {
 	return name, nil
 }
 
+// GetRelativePathNoEval returns the relative path of a given path like
+// GetRelativePath, but purely lexically and without cleaning ".." segments.
+// When a directory is a symlink, "link/.." is its target's parent rather than
+// the directory holding link, so rather than resolving "link/.." to nothing
+// it is kept in the result.
+// An error is returned when only one path is absolute, or when base has a
+// ".." segment that would need resolving to find its relation to path.
+func GetRelativePathNoEval(path, base string) (string, error) {
+	if filepath.IsAbs(path) != filepath.IsAbs(base) {
+		return "", fmt.Errorf("cannot make %s relative to %s", path, base)
+	}
+	pathParts := withoutDotSegments(splitComponents(path))
+	baseParts := withoutDotSegments(splitComponents(base))
+	n := 0
+	for n < len(pathParts) && n < len(baseParts) && pathParts[n] == baseParts[n] && pathParts[n] != ".." {
+		n++
+	}
+	var rel []string
+	for _, p := range baseParts[n:] {
+		if p == ".." {
+			return "", fmt.Errorf("cannot make %s relative to %s without evaluating ..", path, base)
+		}
+		rel = append(rel, "..")
+	}
+	rel = append(rel, pathParts[n:]...)
+	name := joinComponents(rel)
+	if name == "" {
+		name = "."
+	}
+	if strings.HasSuffix(filepath.ToSlash(path), "/") && name != "." {
+		name += FilePathSeparator
+	}
+	return name, nil
+}
+
+// withoutDotSegments drops empty and "." components, keeping ".."
+func withoutDotSegments(parts []string) []string {
+	kept := []string{}
+	for _, p := range parts {
+		if p != "" && p != "." {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// GetRelativePathSep is the same as GetRelativePath but joins the
+// components of the result, and any trailing separator, with sep.
+//  GetRelativePathSep("src/pkg/util", "src", "::") --> pkg::util
+func GetRelativePathSep(path, base, sep string) (string, error) {
+	name, err := GetRelativePath(path, base)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Split(filepath.ToSlash(name), "/"), sep), nil
+}
+
 // ExtractRootPaths extracts the root paths from the supplied list of paths.
 // The resulting root path will not contain any file separators, but there
 // may be duplicates.
@@ -118,6 +214,21 @@ func ExtractRootPaths(paths []string) []string {
 
 }
 
+// TrimRoot removes the root path, as found by ExtractRootPaths, and
+// returns the remainder of the path.
+// So "/content/section/page.md" becomes "section/page.md"
+func TrimRoot(path string) string {
+	sections := strings.Split(filepath.ToSlash(path), "/")
+	for i, section := range sections {
+		if section != "" {
+			// a doubled separator after the root must not leave an absolute path
+			rest := strings.TrimLeft(strings.Join(sections[i+1:], "/"), "/")
+			return filepath.FromSlash(rest)
+		}
+	}
+	return ""
+}
+
 func getRealFileInfo(fs afero.Fs, path string) (os.FileInfo, string, error) {
 	fileInfo, err := lstatIfOs(fs, path)
 	realPath := path
@@ -210,6 +321,25 @@ func Exists(fs afero.Fs, path string) (bool, error) {
 	return afero.Exists(fs, path)
 }
 
+// UniquePath returns path if nothing exists there, otherwise a number is
+// inserted before the extension until an unused path is found.
+//  run001.mod --> run001.1.mod --> run001.2.mod
+func UniquePath(fs afero.Fs, path string) (string, error) {
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	candidate := path
+	for i := 1; ; i++ {
+		exists, err := Exists(fs, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s.%d%s", stem, i, ext)
+	}
+}
+
 // ListDirNames returns an array of directory names from an array of fileinfo
 // AppFs := afero.NewOsFs()
 // dir := filepath.Dir(".")
@@ -234,10 +364,53 @@ func ListDirNames(fd []os.FileInfo) []string {
 func ListFiles(fd []os.FileInfo) []string {
 	files := []string{}
 	for _, pFile := range fd {
-		if !pFile.IsDir() && !strings.HasPrefix(pFile.Name(), ".") {
+		if isVisibleFile(pFile) {
 			//fmt.Printf("%v: %s\n", i, pDir.Name())
 			files = append(files, pFile.Name())
 		}
 	}
 	return files
 }
+
+// IsJunkFile checks if a file name is metadata left behind by an operating
+// system: .DS_Store and AppleDouble "._" files from macOS, or Thumbs.db
+// and desktop.ini from windows, which are matched case-insensitively.
+func IsJunkFile(name string) bool {
+	switch strings.ToLower(name) {
+	case "thumbs.db", "desktop.ini":
+		return true
+	}
+	return name == ".DS_Store" || strings.HasPrefix(name, "._")
+}
+
+// ListFilesNoJunk is the same as ListFiles but also excludes
+// files recognized by IsJunkFile
+func ListFilesNoJunk(fd []os.FileInfo) []string {
+	files := []string{}
+	for _, name := range ListFiles(fd) {
+		if !IsJunkFile(name) {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// ListFilesBetween is the same as ListFiles but only includes files
+// modified within start and end, inclusive. A window ending before it
+// starts matches nothing.
+func ListFilesBetween(fd []os.FileInfo, start, end time.Time) []string {
+	files := []string{}
+	for _, pFile := range fd {
+		mod := pFile.ModTime()
+		if isVisibleFile(pFile) && !mod.Before(start) && !mod.After(end) {
+			files = append(files, pFile.Name())
+		}
+	}
+	return files
+}
+
+// isVisibleFile checks if fileinfo is a file that is not a dotfile,
+// the entries ListFiles returns
+func isVisibleFile(info os.FileInfo) bool {
+	return !info.IsDir() && !strings.HasPrefix(info.Name(), ".")
+}
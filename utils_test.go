@@ -1,7 +1,13 @@
 package goutils
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/afero"
 )
 
 func TestFileAndExt(t *testing.T) {
@@ -30,3 +36,316 @@ func TestFileAndExt(t *testing.T) {
 		}
 	}
 }
+
+func TestTrimRoot(t *testing.T) {
+	type test struct {
+		input    string
+		expected string
+	}
+	data := []test{
+		{"content/section/page.md", "section/page.md"},
+		{"/content/section/page.md", "section/page.md"},
+		{"//content/section/", "section/"},
+		{"content//section/page.md", "section/page.md"},
+		{"/content///section", "section"},
+		{"content//", ""},
+		{"content", ""},
+		{"/content", ""},
+		{"", ""},
+	}
+
+	for i, d := range data {
+		res := TrimRoot(filepath.FromSlash(d.input))
+		if filepath.FromSlash(d.expected) != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestExtensionIndex(t *testing.T) {
+	type test struct {
+		input    string
+		expected int
+	}
+	data := []test{
+		{"test.txt", 4},
+		{"path/run001.mod", 11},
+		{"archive.tar.gz", 11},
+		{"../relative.path/file", -1},
+		{"Makefile", -1},
+		{"path/", -1},
+		{".", -1},
+		{"..", -1},
+		{".bashrc", 0},
+		{"path/.bashrc", 5},
+		{"", -1},
+	}
+
+	for i, d := range data {
+		res := ExtensionIndex(filepath.FromSlash(d.input))
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %d got %d", i, d.expected, res)
+		}
+	}
+}
+
+func TestUniquePath(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/data/run001.mod", nil, 0644)
+	afero.WriteFile(fs, "/data/run001.1.mod", nil, 0644)
+	afero.WriteFile(fs, "/data/Makefile", nil, 0644)
+	type test struct {
+		input    string
+		expected string
+	}
+	data := []test{
+		{"/data/run002.mod", "/data/run002.mod"},
+		{"/data/run001.mod", "/data/run001.2.mod"},
+		{"/data/Makefile", "/data/Makefile.1"},
+		{"/data", "/data.1"},
+	}
+
+	for i, d := range data {
+		res, err := UniquePath(fs, d.input)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestIsJunkFile(t *testing.T) {
+	type test struct {
+		input    string
+		expected bool
+	}
+	data := []test{
+		{".DS_Store", true},
+		{"._run001.mod", true},
+		{"._", true},
+		{"Thumbs.db", true},
+		{"thumbs.db", true},
+		{"desktop.ini", true},
+		{"Desktop.ini", true},
+		{"run001.mod", false},
+		{"DS_Store", false},
+		{"my_Thumbs.db", false},
+		{".gitignore", false},
+	}
+
+	for i, d := range data {
+		res := IsJunkFile(d.input)
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %v got %v for %s", i, d.expected, res, d.input)
+		}
+	}
+}
+
+func TestListFilesNoJunk(t *testing.T) {
+	fd := []os.FileInfo{
+		fakeFileInfo{name: "run001.mod"},
+		fakeFileInfo{name: ".DS_Store"},
+		fakeFileInfo{name: "._run001.mod"},
+		fakeFileInfo{name: "Thumbs.db"},
+		fakeFileInfo{name: "desktop.ini"},
+		fakeFileInfo{name: ".hidden"},
+		fakeFileInfo{name: "run001", mode: os.ModeDir},
+		fakeFileInfo{name: "run002.mod"},
+	}
+	expected := []string{"run001.mod", "run002.mod"}
+
+	res := ListFilesNoJunk(fd)
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %v got %v", expected, res)
+	}
+	for i, e := range expected {
+		if e != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, res[i])
+		}
+	}
+}
+
+func TestEnsureExtension(t *testing.T) {
+	type test struct {
+		path     string
+		ext      string
+		expected string
+	}
+	data := []test{
+		{"report.gz", "gz", "report.gz"},
+		{"report.GZ", "gz", "report.GZ"},
+		{"report.gz", ".GZ", "report.gz"},
+		{"report", "gz", "report.gz"},
+		{"report.tar", "gz", "report.tar.gz"},
+		{"report.tar", ".gz", "report.tar.gz"},
+		{"path/to/run001.mod", "mod", "path/to/run001.mod"},
+		{"path/to/run001.lst", "mod", "path/to/run001.lst.mod"},
+		{"archive.tgz", "gz", "archive.tgz.gz"},
+		{"", "txt", ".txt"},
+		{"report", "", "report"},
+		{"report.tar", ".", "report.tar"},
+	}
+
+	for i, d := range data {
+		res := EnsureExtension(filepath.FromSlash(d.path), d.ext)
+		if filepath.FromSlash(d.expected) != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestListFilesBetween(t *testing.T) {
+	start := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	fd := []os.FileInfo{
+		fakeFileInfo{name: "before.log", modTime: start.Add(-time.Second)},
+		fakeFileInfo{name: "start.log", modTime: start},
+		fakeFileInfo{name: "middle.log", modTime: start.Add(12 * time.Hour)},
+		fakeFileInfo{name: ".hidden", modTime: start.Add(12 * time.Hour)},
+		fakeFileInfo{name: "dir", mode: os.ModeDir, modTime: start.Add(12 * time.Hour)},
+		fakeFileInfo{name: "end.log", modTime: end},
+		fakeFileInfo{name: "after.log", modTime: end.Add(time.Second)},
+	}
+
+	type test struct {
+		start    time.Time
+		end      time.Time
+		expected []string
+	}
+	data := []test{
+		{start, end, []string{"start.log", "middle.log", "end.log"}},
+		{start.Add(time.Hour), end.Add(-time.Hour), []string{"middle.log"}},
+		{start, start, []string{"start.log"}},
+		{end, start, []string{}},
+	}
+	for i, d := range data {
+		res := ListFilesBetween(fd, d.start, d.end)
+		if strings.Join(d.expected, ",") != strings.Join(res, ",") {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+		}
+	}
+}
+
+func TestSplitAll(t *testing.T) {
+	type splitAll struct {
+		dir  string
+		name string
+		ext  string
+	}
+	type test struct {
+		input    string
+		expected splitAll
+	}
+	data := []test{
+		{"path/to/run001.mod", splitAll{"path/to", "run001", "mod"}},
+		{"/absolutepath/test.txt", splitAll{"/absolutepath", "test", "txt"}},
+		{"test.tar.gz", splitAll{".", "test.tar", "gz"}},
+		{"path/Makefile", splitAll{"path", "Makefile", ""}},
+		{"path/.bashrc", splitAll{"path", "", "bashrc"}},
+	}
+
+	for i, d := range data {
+		dir, name, ext := SplitAll(filepath.FromSlash(d.input))
+		if filepath.FromSlash(d.expected.dir) != dir {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.dir, dir)
+		}
+		if d.expected.name != name {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.name, name)
+		}
+		if d.expected.ext != ext {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected.ext, ext)
+		}
+	}
+}
+
+func TestGetRelativePathSep(t *testing.T) {
+	type test struct {
+		path     string
+		base     string
+		sep      string
+		expected string
+	}
+	data := []test{
+		{"src/pkg/util", "src", "/", "pkg/util"},
+		{"src/pkg/util", "src", "::", "pkg::util"},
+		{"src/pkg/util/", "src", "::", "pkg::util::"},
+		{"/abs/src/pkg", "/abs", ".", "src.pkg"},
+		{"src/a", "src/b", "::", "..::a"},
+		{"src", "src", "::", "."},
+	}
+
+	for i, d := range data {
+		res, err := GetRelativePathSep(filepath.FromSlash(d.path), filepath.FromSlash(d.base), d.sep)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if d.expected != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+
+	if _, err := GetRelativePathSep(filepath.FromSlash("/abs/src"), "", "::"); err == nil {
+		t.Error("Expected error for missing base")
+	}
+}
+
+func TestGetRelativePathNoEval(t *testing.T) {
+	type test struct {
+		path     string
+		base     string
+		expected string
+		err      bool
+	}
+	data := []test{
+		{"a/b/c", "a", "b/c", false},
+		{"a/link/../c", "a", "link/../c", false},
+		{"a/./b//c/", "a", "b/c/", false},
+		{"a/b", "a/c/d", "../../b", false},
+		{"/a/b", "/a/b", ".", false},
+		{"a/b", "a/../c", "", true},
+		{"/a/b", "a", "", true},
+	}
+
+	for i, d := range data {
+		res, err := GetRelativePathNoEval(filepath.FromSlash(d.path), filepath.FromSlash(d.base))
+		if (err != nil) != d.err {
+			t.Errorf("Test %d failed. Unexpected error state %v", i, err)
+		}
+		if filepath.FromSlash(d.expected) != res {
+			t.Errorf("Test %d failed. Expected %s got %s", i, d.expected, res)
+		}
+	}
+}
+
+func TestGetRelativePathNoEvalSymlink(t *testing.T) {
+	fs, root := tempSymlinkDir(t)
+	defer os.RemoveAll(root)
+	// root/link points into root/other/deep, so root/link/.. is root/other
+	fs.MkdirAll(filepath.Join(root, "other", "deep"), 0755)
+	afero.WriteFile(fs, filepath.Join(root, "other", "file.txt"), []byte("file"), 0644)
+	os.Symlink(filepath.Join(root, "other", "deep"), filepath.Join(root, "link"))
+	path := filepath.Join(root, "link") + FilePathSeparator + filepath.Join("..", "file.txt")
+
+	cleaned, _ := GetRelativePath(path, root)
+	if cleaned != "file.txt" {
+		t.Errorf("Expected GetRelativePath to clean to file.txt got %s", cleaned)
+	}
+	if _, err := os.Stat(filepath.Join(root, cleaned)); err == nil {
+		t.Error("Expected the cleaned path to no longer point to the file")
+	}
+
+	res, err := GetRelativePathNoEval(path, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// built by hand as filepath.Join would clean away the ..
+	expected := strings.Join([]string{"link", "..", "file.txt"}, FilePathSeparator)
+	if expected != res {
+		t.Errorf("Expected %s got %s", expected, res)
+	}
+	if _, err := os.Stat(root + FilePathSeparator + res); err != nil {
+		t.Errorf("Expected the preserved path to still point to the file: %s", err)
+	}
+}
@@ -0,0 +1,199 @@
+package goutils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// TreeRelativePaths walks root and returns the path of every file relative to
+// root, slash separated and sorted. Dotfiles are included.
+func TreeRelativePaths(fs afero.Fs, root string) ([]string, error) {
+	var paths []string
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// OrderedFiles returns the files below root in a stable order, with entries
+// of each directory visited alphabetically.
+// Depth first, a directory's contents are listed at the point the directory
+// is reached:
+//  a/1.txt, a/sub/x.txt, b.txt
+// Breadth first, all files of a level are listed before those of the next:
+//  b.txt, a/1.txt, a/sub/x.txt
+// Returned paths are joined to root, as with afero.Walk. Symlinks are not followed.
+func OrderedFiles(fs afero.Fs, root string, breadthFirst bool) ([]string, error) {
+	if breadthFirst {
+		return orderedFilesBreadthFirst(fs, root)
+	}
+	return orderedFilesDepthFirst(fs, root, nil)
+}
+
+func orderedFilesDepthFirst(fs afero.Fs, dir string, files []string) ([]string, error) {
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if e.IsDir() {
+			files, err = orderedFilesDepthFirst(fs, path, files)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func orderedFilesBreadthFirst(fs afero.Fs, root string) ([]string, error) {
+	var files []string
+	queue := []string{root}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		entries, err := afero.ReadDir(fs, dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			if e.IsDir() {
+				queue = append(queue, path)
+				continue
+			}
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// DistinctExtensions walks root and returns the sorted set of file
+// extensions, without the dot, as given by FileAndExt. An empty string is
+// included if any file has no extension.
+func DistinctExtensions(fs afero.Fs, root string) ([]string, error) {
+	paths, err := TreeRelativePaths(fs, root)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	exts := []string{}
+	for _, p := range paths {
+		_, ext := FileAndExt(p)
+		ext = strings.TrimPrefix(ext, ".")
+		if !seen[ext] {
+			seen[ext] = true
+			exts = append(exts, ext)
+		}
+	}
+	sort.Strings(exts)
+	return exts, nil
+}
+
+// StreamFiles walks root in the background, sending the path of each file
+// relative to root as it is found, so very large trees can be processed
+// without building a slice. Once the walk ends the paths channel is closed
+// and a single error, nil on success, is sent on the error channel.
+// Canceling ctx stops the walk and reports ctx.Err().
+func StreamFiles(ctx context.Context, fs afero.Fs, root string) (<-chan string, <-chan error) {
+	paths := make(chan string)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(paths)
+		errc <- afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if info.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			select {
+			case paths <- rel:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return paths, errc
+}
+
+// PathInfo pairs a walked path with its fileinfo
+type PathInfo struct {
+	Path string
+	Info os.FileInfo
+}
+
+// WalkInfo walks root and returns every entry, including root itself and
+// dotfiles, with the fileinfo gathered during the walk so callers needing
+// sizes or modtimes do not have to stat each path again.
+func WalkInfo(fs afero.Fs, root string) ([]PathInfo, error) {
+	var entries []PathInfo
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		entries = append(entries, PathInfo{path, info})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// errStopWalk ends a walk early once its result is known
+var errStopWalk = errors.New("stop walk")
+
+// AnyNewerThan walks root and returns the path of the first file, in walk
+// order, modified after ref. The walk stops as soon as one is found.
+func AnyNewerThan(fs afero.Fs, root string, ref time.Time) (string, bool, error) {
+	var found string
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !info.ModTime().After(ref) {
+			return nil
+		}
+		found = path
+		return errStopWalk
+	})
+	if err != nil && err != errStopWalk {
+		return "", false, err
+	}
+	return found, found != "", nil
+}
@@ -0,0 +1,282 @@
+package goutils
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// newTestTree creates a MemMapFs containing the given slash separated files
+// below root, each file containing its own relative path
+func newTestTree(root string, files []string) afero.Fs {
+	fs := afero.NewMemMapFs()
+	for _, f := range files {
+		afero.WriteFile(fs, filepath.Join(root, filepath.FromSlash(f)), []byte(f), 0644)
+	}
+	return fs
+}
+
+func TestTreeRelativePaths(t *testing.T) {
+	fs := newTestTree("/site", []string{
+		"index.html",
+		"b/page.html",
+		"a/z.css",
+		"a/nested/deep/file.js",
+		".htaccess",
+		"a/.hidden",
+	})
+	fs.MkdirAll(filepath.FromSlash("/site/empty"), 0755)
+	expected := []string{
+		".htaccess",
+		"a/.hidden",
+		"a/nested/deep/file.js",
+		"a/z.css",
+		"b/page.html",
+		"index.html",
+	}
+
+	res, err := TreeRelativePaths(fs, filepath.FromSlash("/site"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %v got %v", expected, res)
+	}
+	for i, e := range expected {
+		if e != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e, res[i])
+		}
+	}
+}
+
+func TestOrderedFiles(t *testing.T) {
+	fs := newTestTree("/root", []string{
+		"z.txt",
+		"c/y.txt",
+		"b.txt",
+		"a/sub/x.txt",
+		"a/2.txt",
+		"a/1.txt",
+	})
+	type test struct {
+		breadthFirst bool
+		expected     []string
+	}
+	data := []test{
+		{false, []string{"a/1.txt", "a/2.txt", "a/sub/x.txt", "b.txt", "c/y.txt", "z.txt"}},
+		{true, []string{"b.txt", "z.txt", "a/1.txt", "a/2.txt", "c/y.txt", "a/sub/x.txt"}},
+	}
+
+	root := filepath.FromSlash("/root")
+	for i, d := range data {
+		res, err := OrderedFiles(fs, root, d.breadthFirst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != len(d.expected) {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+			continue
+		}
+		for j, e := range d.expected {
+			if filepath.Join(root, filepath.FromSlash(e)) != res[j] {
+				t.Errorf("Test %d failed. Expected %s got %s", i, e, res[j])
+			}
+		}
+	}
+
+	if _, err := OrderedFiles(fs, "/missing", false); err == nil {
+		t.Error("Expected error for missing root")
+	}
+}
+
+func TestDistinctExtensions(t *testing.T) {
+	type test struct {
+		files    []string
+		expected []string
+	}
+	data := []test{
+		{
+			[]string{"a.go", "b.go", "sub/c.md", "sub/deep/d.go", "e.tar.gz", "img/logo.PNG"},
+			[]string{"PNG", "go", "gz", "md"},
+		},
+		{
+			[]string{"Makefile", "main.go", "sub/LICENSE"},
+			[]string{"", "go"},
+		},
+		{
+			[]string{},
+			[]string{},
+		},
+	}
+
+	for i, d := range data {
+		fs := newTestTree("/root", d.files)
+		fs.MkdirAll("/root", 0755)
+		res, err := DistinctExtensions(fs, "/root")
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		if len(res) != len(d.expected) {
+			t.Errorf("Test %d failed. Expected %v got %v", i, d.expected, res)
+			continue
+		}
+		for j, e := range d.expected {
+			if e != res[j] {
+				t.Errorf("Test %d failed. Expected %s got %s", i, e, res[j])
+			}
+		}
+	}
+}
+
+func TestStreamFiles(t *testing.T) {
+	var files []string
+	for i := 0; i < 100; i++ {
+		files = append(files, fmt.Sprintf("dir%02d/file.txt", i))
+	}
+	fs := newTestTree("/root", files)
+
+	paths, errc := StreamFiles(context.Background(), fs, "/root")
+	var res []string
+	for p := range paths {
+		res = append(res, p)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(files) {
+		t.Fatalf("Expected %d files got %d", len(files), len(res))
+	}
+	for i, f := range files {
+		if filepath.FromSlash(f) != res[i] {
+			t.Errorf("Test %d failed. Expected %s got %s", i, f, res[i])
+		}
+	}
+}
+
+func TestStreamFilesCancel(t *testing.T) {
+	var files []string
+	for i := 0; i < 100; i++ {
+		files = append(files, fmt.Sprintf("dir%02d/file.txt", i))
+	}
+	fs := newTestTree("/root", files)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	paths, errc := StreamFiles(ctx, fs, "/root")
+	received := 0
+	for range paths {
+		received++
+		if received == 5 {
+			cancel()
+			break
+		}
+	}
+	// anything still in flight is drained until the walk stops
+	for range paths {
+		received++
+	}
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("Expected %v got %v", context.Canceled, err)
+	}
+	if received > 6 {
+		t.Errorf("Expected walk to stop after cancel, received %d files", received)
+	}
+
+	if _, errc := StreamFiles(context.Background(), fs, "/missing"); <-errc == nil {
+		t.Error("Expected error for missing root")
+	}
+}
+
+func TestWalkInfo(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	fs.MkdirAll("/root/sub", 0755)
+	afero.WriteFile(fs, "/root/a.txt", []byte("a"), 0644)
+	afero.WriteFile(fs, "/root/.hidden", []byte("hidden"), 0644)
+	afero.WriteFile(fs, "/root/sub/b.txt", []byte("bb"), 0644)
+	type entry struct {
+		path  string
+		isDir bool
+		size  int64
+	}
+	expected := []entry{
+		{"/root", true, 0},
+		{"/root/.hidden", false, 6},
+		{"/root/a.txt", false, 1},
+		{"/root/sub", true, 0},
+		{"/root/sub/b.txt", false, 2},
+	}
+
+	res, err := WalkInfo(fs, filepath.FromSlash("/root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(expected) {
+		t.Fatalf("Expected %d entries got %d", len(expected), len(res))
+	}
+	for i, e := range expected {
+		if filepath.FromSlash(e.path) != res[i].Path {
+			t.Errorf("Test %d failed. Expected %s got %s", i, e.path, res[i].Path)
+		}
+		if filepath.Base(res[i].Path) != res[i].Info.Name() {
+			t.Errorf("Test %d failed. Expected info for %s got %s", i, res[i].Path, res[i].Info.Name())
+		}
+		if e.isDir != res[i].Info.IsDir() {
+			t.Errorf("Test %d failed. Expected IsDir %v got %v", i, e.isDir, res[i].Info.IsDir())
+		}
+		if !e.isDir && e.size != res[i].Info.Size() {
+			t.Errorf("Test %d failed. Expected size %d got %d", i, e.size, res[i].Info.Size())
+		}
+	}
+}
+
+func TestAnyNewerThan(t *testing.T) {
+	ref := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := ref.Add(-time.Hour)
+	files := []string{"a.txt", "sub/b.txt", "sub/c.txt", "z.txt"}
+
+	type test struct {
+		modTimes map[string]time.Time
+		expected string
+	}
+	data := []test{
+		{nil, ""},
+		{map[string]time.Time{"sub/c.txt": ref.Add(time.Second)}, "sub/c.txt"},
+		{map[string]time.Time{"z.txt": ref.Add(time.Hour), "sub/b.txt": ref.Add(time.Second)}, "sub/b.txt"},
+		// an equal modtime is not newer
+		{map[string]time.Time{"a.txt": ref}, ""},
+	}
+	for i, d := range data {
+		fs := newTestTree("/src", files)
+		for _, f := range files {
+			modTime, ok := d.modTimes[f]
+			if !ok {
+				modTime = old
+			}
+			fs.Chtimes(filepath.Join("/src", filepath.FromSlash(f)), modTime, modTime)
+		}
+		path, ok, err := AnyNewerThan(fs, filepath.FromSlash("/src"), ref)
+		if err != nil {
+			t.Errorf("Test %d failed. Unexpected error %s", i, err)
+		}
+		expected := ""
+		if d.expected != "" {
+			expected = filepath.Join(filepath.FromSlash("/src"), filepath.FromSlash(d.expected))
+		}
+		if expected != path || ok != (d.expected != "") {
+			t.Errorf("Test %d failed. Expected %s got %s, %v", i, expected, path, ok)
+		}
+	}
+
+	fs := afero.NewMemMapFs()
+	fs.MkdirAll(filepath.FromSlash("/empty"), 0755)
+	if path, ok, err := AnyNewerThan(fs, filepath.FromSlash("/empty"), ref); ok || path != "" || err != nil {
+		t.Errorf("Expected nothing newer in an empty tree got %s, %v, %v", path, ok, err)
+	}
+	if _, _, err := AnyNewerThan(fs, "/missing", ref); err == nil {
+		t.Error("Expected error for missing root")
+	}
+}